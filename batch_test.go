@@ -0,0 +1,69 @@
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit"
+)
+
+func TestBatchOrders(t *testing.T) {
+	var batches []int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v5/order/create-batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request []map[string]any `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batches = append(batches, len(body.Request))
+
+		list := make([]map[string]any, len(body.Request))
+		ext := make([]map[string]any, len(body.Request))
+		for i, item := range body.Request {
+			list[i] = map[string]any{"orderId": fmt.Sprintf("%v-%d", item["symbol"], i)}
+			ext[i] = map[string]any{"code": 0, "msg": "OK"}
+			if item["qty"] == "0" {
+				list[i]["orderId"] = ""
+				ext[i] = map[string]any{"code": 10001, "msg": "invalid qty"}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"retCode":    0,
+			"result":     map[string]any{"list": list},
+			"retExtInfo": map[string]any{"list": ext},
+		})
+	})
+	mux.HandleFunc("/v5/order/cancel-all", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"retCode":0,"result":{"list":[{"orderId":"a"},{"orderId":"b"}]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cli := bybit.NewClient("key", "secret", bybit.WithBaseURL(server.URL))
+	price := 100.
+	orders := make([]broker.OrderParams, 25)
+	for i := range orders {
+		orders[i] = broker.OrderParams{Symbol: "BTCUSDT", Qty: .01, Price: &price}
+	}
+	orders[21].Qty = 0
+
+	results, err := cli.BrokerImpl().PlaceOrders(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || batches[0] != 20 || batches[1] != 5 || len(results) != 25 {
+		t.Fatalf("unexpected batches: %v (%d results)", batches, len(results))
+	}
+	if results[21].Err == nil || results[20].Err != nil || results[20].OrderId != "BTCUSDT-0" {
+		t.Fatalf("unexpected results: %+v %+v", results[20], results[21])
+	}
+
+	orderIds, err := cli.CancelAllOrders("BTCUSDT")
+	if err != nil || len(orderIds) != 2 {
+		t.Fatalf("unexpected cancelled orders: %v %v", orderIds, err)
+	}
+}
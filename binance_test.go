@@ -0,0 +1,159 @@
+package main_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nikita55612/goTradingBot/internal/broker/binance"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+func TestBinanceBroker(t *testing.T) {
+	var orderStatus atomic.Value
+	orderStatus.Store("PARTIALLY_FILLED")
+	var tradesCalls atomic.Int32
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fapi/v1/exchangeInfo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","quoteAsset":"USDT","quantityPrecision":3,"filters":[
+			{"filterType":"PRICE_FILTER","tickSize":"0.10"},
+			{"filterType":"LOT_SIZE","stepSize":"0.001"},
+			{"filterType":"MIN_NOTIONAL","notional":"100"}]}]}`))
+	})
+	mux.HandleFunc("/fapi/v1/klines", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[[60000,"1","2","0.5","1.5","10",119999,"15",3,"0","0","0"],
+			[120000,"1.5","3","1","2","20",179999,"40",5,"0","0","0"]]`))
+	})
+	mux.HandleFunc("/fapi/v1/order", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		signature := query.Get("signature")
+		payload := strings.TrimSuffix(r.URL.RawQuery, "&signature="+signature)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(payload))
+		if r.Header.Get("X-MBX-APIKEY") != "key" || hex.EncodeToString(mac.Sum(nil)) != signature {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-1022,"msg":"Signature for this request is not valid."}`))
+			return
+		}
+		if r.Method == http.MethodPost && (query.Get("side") != "SELL" || query.Get("type") != "LIMIT" || query.Get("quantity") != "0.01") {
+			t.Errorf("unexpected order params: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"orderId":42,"symbol":"BTCUSDT","status":"%s","side":"SELL",
+			"price":"100","avgPrice":"100","origQty":"0.01","executedQty":"0.005","cumQuote":"0.5",
+			"time":1,"updateTime":2}`, orderStatus.Load())
+	})
+	mux.HandleFunc("/fapi/v1/userTrades", func(w http.ResponseWriter, r *http.Request) {
+		tradesCalls.Add(1)
+		w.Write([]byte(`[{"orderId":42,"commission":"0.01","commissionAsset":"USDT"},
+			{"orderId":42,"commission":"0.00005","commissionAsset":"BNB"}]`))
+	})
+	mux.HandleFunc("/fapi/v1/ticker/price", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") != "BNBUSDT" {
+			t.Errorf("unexpected price symbol: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"symbol":"BNBUSDT","price":"400","time":1}`))
+	})
+	mux.HandleFunc("/ws/btcusdt@kline_1m", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"e":"kline","E":1,"s":"BTCUSDT","k":{
+			"t":60000,"T":119999,"i":"1m","o":"1","c":"2","h":"3","l":"0.5","v":"10","q":"20","x":true}}`))
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cli := binance.NewClient(
+		"key", "secret",
+		binance.WithBaseURL(server.URL),
+		binance.WithWSURL("ws"+strings.TrimPrefix(server.URL, "http")),
+	)
+	b := cli.BrokerImpl()
+
+	data, err := b.GetInstrumentInfo("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info map[string]any
+	json.Unmarshal(data, &info)
+	if info["qtyPrecision"] != 3. || info["minOrderAmt"] != 100. || info["tickSize"] != .1 || info["category"] != "linear" {
+		t.Fatalf("unexpected instrument info: %s", data)
+	}
+	if _, err := b.GetInstrumentInfo("ETHUSDT"); err == nil {
+		t.Fatal("expected error for unknown symbol")
+	}
+
+	candles, err := b.GetCandles("BTCUSDT", cdl.M1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 2 || candles[0].Time != 60000 || candles[1].C != 2 || candles[1].Turnover != 40 {
+		t.Fatalf("unexpected candles: %+v", candles)
+	}
+
+	price := 100.
+	orderId, err := b.PlaceOrder("BTCUSDT", -.01, &price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orderId != "BTCUSDT:42" {
+		t.Fatalf("unexpected order id: %s", orderId)
+	}
+	data, err = b.GetOrder(orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order map[string]any
+	json.Unmarshal(data, &order)
+	if order["id"] != orderId || order["qty"] != -.01 || order["execValue"] != -.5 || order["fee"] != 0. || order["isClosed"] != false {
+		t.Fatalf("unexpected order: %s", data)
+	}
+	// Сделки для расчета комиссии запрашиваются только после закрытия ордера
+	if tradesCalls.Load() != 0 {
+		t.Fatalf("unexpected trades requests for open order: %d", tradesCalls.Load())
+	}
+	orderStatus.Store("CANCELED")
+	data, err = b.GetOrder(orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(data, &order)
+	// Комиссия в BNB пересчитывается в котируемую монету: 0.01 + 0.00005*400
+	if fee, _ := order["fee"].(float64); !almostEqual(fee, .03) || order["isClosed"] != true || tradesCalls.Load() != 1 {
+		t.Fatalf("unexpected closed order: %s", data)
+	}
+	if _, err := binance.NewClient("key", "wrong", binance.WithBaseURL(server.URL)).GetOrder(orderId); err == nil {
+		t.Fatal("expected signature error")
+	} else if e, ok := err.(*binance.Error); !ok || e.ServerResponseCode() != -1022 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := b.CandleStream(ctx, "BTCUSDT", cdl.M1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case data := <-stream:
+		if !data.Confirm || data.Candle.Time != 119999 || data.Candle.H != 3 || data.Interval != cdl.M1 {
+			t.Fatalf("unexpected stream data: %+v", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no stream data")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/credentials"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+)
+
+const credentialsUsage = `usage: goTradingBot credentials [-store path] <command>
+
+commands:
+  add [-exchange bybit|binance|okx] <name>  add or replace credentials (keys are prompted)
+  list                                     list stored credentials with redacted keys
+  remove <name>                            remove credentials
+`
+
+// runCredentialsCommand выполняет подкоманду управления зашифрованным хранилищем ключей
+func runCredentialsCommand(args []string) error {
+	fs := flag.NewFlagSet("credentials", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, credentialsUsage) }
+	storePath := fs.String("store", credentials.DefaultStorePath, "path to credentials store")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("command is required")
+	}
+
+	passphrase, err := credentials.ReadPassphrase()
+	if err != nil {
+		return err
+	}
+	store, err := credentials.Open(*storePath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	if cmd == "add" && store.IsNew() && os.Getenv(credentials.PassphraseEnv) == "" {
+		// Опечатка в парольной фразе нового хранилища лишит доступа к нему
+		confirm, err := credentials.Prompt("confirm credentials store passphrase: ", true)
+		if err != nil {
+			return err
+		}
+		if confirm != passphrase {
+			return errors.New("passphrases do not match")
+		}
+	}
+
+	switch cmd {
+	case "add":
+		addFs := flag.NewFlagSet("add", flag.ContinueOnError)
+		exchange := addFs.String("exchange", trading.ExchangeBybit, "exchange of the account")
+		if err := addFs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if addFs.NArg() != 1 {
+			return errors.New("credentials name is required")
+		}
+		c := credentials.Credential{Name: addFs.Arg(0), Exchange: *exchange}
+		if c.APIKey, err = credentials.Prompt("api key: ", false); err != nil {
+			return err
+		}
+		if c.APISecret, err = credentials.Prompt("api secret: ", true); err != nil {
+			return err
+		}
+		if c.Exchange == trading.ExchangeOKX {
+			if c.Passphrase, err = credentials.Prompt("api passphrase: ", true); err != nil {
+				return err
+			}
+		}
+		if err := store.Put(c); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("credentials saved: %s\n", c)
+
+	case "list":
+		for _, c := range store.List() {
+			fmt.Println(c)
+		}
+
+	case "remove":
+		if len(cmdArgs) != 1 {
+			return errors.New("credentials name is required")
+		}
+		if !store.Remove(cmdArgs[0]) {
+			return fmt.Errorf("credentials %s not found", cmdArgs[0])
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("credentials removed: %s\n", cmdArgs[0])
+
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command: %s", cmd)
+	}
+
+	return nil
+}
+
+// accountCredentials возвращает ключи аккаунта из зашифрованного хранилища (credentials.store)
+// или из переменных окружения. Хранилище открывается функцией openStore при первом обращении.
+func accountCredentials(a *trading.AccountConfig, openStore func() (*credentials.Store, error)) (credentials.Credential, error) {
+	if a.Credentials.Store != "" {
+		store, err := openStore()
+		if err != nil {
+			return credentials.Credential{}, fmt.Errorf("account %s: %w", a.Name, err)
+		}
+		c, ok := store.Get(a.Credentials.Store)
+		if !ok {
+			return c, fmt.Errorf("account %s: credentials %s not found in %s", a.Name, a.Credentials.Store, store.Path())
+		}
+		if c.Exchange != a.Exchange {
+			return c, fmt.Errorf("account %s: credentials %s belong to %s", a.Name, c.Name, c.Exchange)
+		}
+		return c, nil
+	}
+
+	prefix := a.Credentials.EnvPrefix(a.Exchange)
+	c := credentials.Credential{
+		Name:       prefix,
+		Exchange:   a.Exchange,
+		APIKey:     os.Getenv(prefix + "_API_KEY"),
+		APISecret:  os.Getenv(prefix + "_API_SECRET"),
+		Passphrase: os.Getenv(prefix + "_API_PASSPHRASE"),
+	}
+	if c.APIKey == "" || c.APISecret == "" {
+		return c, fmt.Errorf("account %s: %s_API_KEY and %s_API_SECRET must be specified", a.Name, prefix, prefix)
+	}
+	return c, nil
+}
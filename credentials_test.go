@@ -0,0 +1,64 @@
+package main_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/credentials"
+)
+
+func TestCredentialsStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	const secret = "super-secret-value-123"
+
+	store, err := credentials.Open(path, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(credentials.Credential{Name: "main", Exchange: "bybit", APIKey: "key-0123456789", APISecret: secret}); err != nil {
+		t.Fatal(err)
+	}
+	store.Put(credentials.Credential{Name: "old", Exchange: "okx", APIKey: "k", APISecret: "s", Passphrase: "p"})
+	if !store.Remove("old") || store.Remove("old") {
+		t.Fatal("unexpected remove result")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte(secret)) || bytes.Contains(data, []byte("key-0123456789")) {
+		t.Fatal("store file contains plaintext secrets")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Fatalf("unexpected store file mode: %v", info.Mode())
+	}
+
+	if _, err := credentials.Open(path, "wrong"); !errors.Is(err, credentials.ErrInvalidPassphrase) {
+		t.Fatalf("expected invalid passphrase error, got %v", err)
+	}
+	store, err = credentials.Open(path, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := store.List()
+	if len(list) != 1 || list[0].APISecret != secret || list[0].Exchange != "bybit" {
+		t.Fatalf("unexpected credentials: %d", len(list))
+	}
+
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("loaded", "credential", list[0])
+	jsonData, _ := json.Marshal(list[0])
+	for _, out := range []string{logs.String(), string(jsonData), fmt.Sprint(list[0]), fmt.Sprintf("%#v", list[0])} {
+		if strings.Contains(out, secret) || strings.Contains(out, "key-0123456789") {
+			t.Fatalf("secret is not redacted: %s", out)
+		}
+	}
+}
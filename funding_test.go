@@ -0,0 +1,72 @@
+package main_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/trading"
+)
+
+// fundingProvider отдает прогноз фандинга: первый расчет в settleAt, следующие - через час после запроса
+type fundingProvider struct {
+	stubProvider
+	settleAt int64
+	calls    atomic.Int32
+}
+
+func (p *fundingProvider) GetFundingInfo(symbol string) ([]byte, error) {
+	next := p.settleAt
+	if now := time.Now().UnixMilli(); now >= next {
+		next = now + time.Hour.Milliseconds()
+	}
+	p.calls.Add(1)
+	return fmt.Appendf(nil, `{"fundingRate":0.001,"nextFundingTime":%d,"fundingInterval":480,"markPrice":100}`, next), nil
+}
+
+func TestFundingGuard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := &fundingProvider{settleAt: time.Now().Add(300 * time.Millisecond).UnixMilli()}
+	ledger := trading.NewPositionLedger(3)
+	ledger.Apply(trading.Fill{Qty: 2, Price: 100})
+	guard := trading.NewFundingGuard(trading.NewSubData(ctx, provider, 10), "BTCUSDT", ledger, .0005, time.Minute)
+
+	// Длинная позиция платит положительную ставку, короткая - получает
+	if !guard.ShouldAvoid(1) || guard.ShouldAvoid(-1) || guard.ShouldAvoid(0) {
+		t.Fatal("unexpected funding guard decision")
+	}
+	if cost, err := guard.Cost(-1); err != nil || !almostEqual(cost, -.001) {
+		t.Fatalf("unexpected funding cost: %f %v", cost, err)
+	}
+	// Прогноз кэшируется до времени расчета
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Fatalf("unexpected funding info requests: %d", calls)
+	}
+
+	go guard.Run(ctx)
+	deadline := time.After(2 * time.Second)
+	for len(ledger.FundingPayments()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("funding payment not booked")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	payments := ledger.FundingPayments()
+	if len(payments) != 1 || payments[0].Time != provider.settleAt || !almostEqual(payments[0].Amount, -.2) {
+		t.Fatalf("unexpected funding payments: %+v", payments)
+	}
+	// После расчета прогноз обновляется, следующий расчет не скоро
+	if guard.ShouldAvoid(1) {
+		t.Fatal("position avoided long before the next funding")
+	}
+
+	noFunding := trading.NewFundingGuard(trading.NewSubData(ctx, &stubProvider{}, 10), "BTCUSDT", nil, 0, time.Minute)
+	if noFunding.ShouldAvoid(1) {
+		t.Fatal("guard without funding data must not avoid positions")
+	}
+}
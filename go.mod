@@ -9,5 +9,9 @@ require github.com/nikita55612/httpx v0.0.0-20250611034533-4c51a8a1f50e
 require (
 	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/crypto v0.43.0
 	golang.org/x/exp v0.0.0-20250606033433-dcc06ee1d476
+	golang.org/x/term v0.36.0
 )
+
+require golang.org/x/sys v0.37.0 // indirect
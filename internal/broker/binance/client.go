@@ -0,0 +1,174 @@
+package binance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/nikita55612/httpx"
+)
+
+const (
+	MAINNET         = "https://fapi.binance.com"
+	TESTNET         = "https://testnet.binancefuture.com"
+	STREAMWS        = "wss://fstream.binance.com"
+	STREAMWSTESTNET = "wss://stream.binancefuture.com"
+)
+
+// ServerResponse представляет ответ API Binance с ошибкой
+type ServerResponse struct {
+	Code int    `json:"code"` // Code - код ошибки
+	Msg  string `json:"msg"`  // Msg - описание ошибки
+}
+
+// Client представляет клиент для работы с REST API фьючерсов USDⓈ-M Binance
+type Client struct {
+	baseURL    string          // базовый URL API (тестовая или основная сеть)
+	wsURL      string          // базовый URL потоков WebSocket
+	apiKey     string          // публичный API-ключ для аутентификации
+	apiSecret  string          // секретный ключ для подписи запросов (HMAC)
+	recvWindow int             // временное окно валидности запроса в миллисекундах
+	ctx        context.Context // контекст для выполнения запросов
+	timeout    time.Duration   // таймаут HTTP-запросов
+}
+
+// NewClient создает новый экземпляр клиента для работы с API Binance
+// Принимает опциональные параметры конфигурации через Option функции
+func NewClient(apiKey, apiSecret string, opts ...Option) *Client {
+	client := &Client{
+		baseURL:    MAINNET,
+		wsURL:      STREAMWS,
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		recvWindow: 5000,
+		timeout:    5 * time.Second,
+	}
+	for _, option := range opts {
+		option(client)
+	}
+	return client
+}
+
+func NewClientFromEnv(opts ...Option) *Client {
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("%s: NewClientFromEnv: error loading .env file", errorTitel)
+	}
+	apiKey := os.Getenv("BINANCE_API_KEY")
+	if apiKey == "" {
+		log.Fatalf("%s: NewClientFromEnv: BINANCE_API_KEY not specified", errorTitel)
+	}
+	apiSecret := os.Getenv("BINANCE_API_SECRET")
+	if apiSecret == "" {
+		log.Fatalf("%s: NewClientFromEnv: BINANCE_API_SECRET not specified", errorTitel)
+	}
+	return NewClient(apiKey, apiSecret, opts...)
+}
+
+// Option определяет тип функции для настройки Client
+type Option func(*Client)
+
+// WithContext устанавливает контекст для выполнения запросов
+func WithContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithTimeout устанавливает таймаут для HTTP-запросов
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRecvWindow устанавливает пользовательское значение recvWindow
+// recvWindow - временное окно валидности запроса в миллисекундах
+func WithRecvWindow(recvWindow int) Option {
+	return func(c *Client) {
+		c.recvWindow = recvWindow
+	}
+}
+
+// WithBaseURL устанавливает пользовательский базовый URL API
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithWSURL устанавливает пользовательский базовый URL потоков WebSocket
+func WithWSURL(url string) Option {
+	return func(c *Client) {
+		c.wsURL = url
+	}
+}
+
+// callAPI выполняет запрос method к path. Параметры передаются в строке запроса,
+// для signed запросов добавляются timestamp, recvWindow и подпись HMAC SHA256.
+func (c *Client) callAPI(method, path string, query url.Values, signed bool, result any) error {
+	if query == nil {
+		query = make(url.Values)
+	}
+	queryString := query.Encode()
+	if signed {
+		query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		query.Set("recvWindow", strconv.Itoa(c.recvWindow))
+		queryString = query.Encode()
+		mac := hmac.New(sha256.New, []byte(c.apiSecret))
+		if _, err := mac.Write([]byte(queryString)); err != nil {
+			err := fmt.Errorf("error when creating the request signature: %w", err)
+			return NewError(UnknownErrorT, err)
+		}
+		queryString = fmt.Sprintf("%s&signature=%s", queryString, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	u := fmt.Sprintf("%s%s", c.baseURL, path)
+	if queryString != "" {
+		u = fmt.Sprintf("%s?%s", u, queryString)
+	}
+	req := httpx.NewRequestBuilder(method, u).WithHeader(
+		"X-MBX-APIKEY", c.apiKey,
+		"Accept", "application/json",
+	)
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	if c.timeout > 0 {
+		req = req.WithTimeout(c.timeout)
+	}
+	res, err := req.Build().Do()
+	if err != nil {
+		return NewError(RequestErrorT, err)
+	}
+	defer res.Close()
+
+	body, err := res.ReadBody()
+	if err != nil {
+		return NewError(RequestErrorT, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		var serverResponse ServerResponse
+		if err := json.Unmarshal(body, &serverResponse); err != nil || serverResponse.Code == 0 {
+			err := fmt.Errorf("unexpected response status: %s", res.Status)
+			return NewError(RequestErrorT, err)
+		}
+		return ErrorFromServerResponse(&serverResponse)
+	}
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return NewError(SerDeErrorT, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/binance/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+func (c *Client) BrokerImpl() broker.Broker {
+	return &BrokerImpl{cli: c}
+}
+
+type BrokerImpl struct {
+	cli *Client
+}
+
+func (b *BrokerImpl) GetInstrumentInfo(symbol string) ([]byte, error) {
+	info, err := b.cli.GetInstrumentInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	qtyPrecision := info.QuantityPrecision
+	if lotSize, ok := info.Filter("LOT_SIZE"); ok {
+		v, parseErr := strconv.ParseFloat(lotSize.StepSize, 64)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		qtyPrecision = numeric.DecimalPlaces(v)
+	}
+	var minOrderAmt float64
+	if minNotional, ok := info.Filter("MIN_NOTIONAL"); ok {
+		v, parseErr := strconv.ParseFloat(minNotional.Notional, 64)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		minOrderAmt = v
+	}
+	priceFilter, ok := info.Filter("PRICE_FILTER")
+	if !ok {
+		return nil, fmt.Errorf("price filter not found for symbol %s", symbol)
+	}
+	tickSize, parseErr := strconv.ParseFloat(priceFilter.TickSize, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	infoData := map[string]any{
+		"qtyPrecision": qtyPrecision,
+		"minOrderAmt":  minOrderAmt,
+		"tickSize":     tickSize,
+		"category":     "linear",
+	}
+
+	return json.Marshal(infoData)
+}
+
+func (b *BrokerImpl) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	return b.cli.GetCandles(symbol, interval, limit)
+}
+
+func (b *BrokerImpl) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	return b.cli.CandleStream(ctx, symbol, interval)
+}
+
+func (b *BrokerImpl) PlaceOrder(symbol string, qty float64, price *float64) (string, error) {
+	return b.cli.PlaceOrder(symbol, qty, price)
+}
+
+func (b *BrokerImpl) CancelOrder(orderId string) (string, error) {
+	return b.cli.CancelOrder(orderId)
+}
+
+func (b *BrokerImpl) PlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchPlaceOrders(orders)
+}
+
+func (b *BrokerImpl) AmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchAmendOrders(orders)
+}
+
+func (b *BrokerImpl) CancelOrders(symbol string, orderIds []string) ([]broker.BatchResult, error) {
+	return b.cli.BatchCancelOrders(symbol, orderIds)
+}
+
+func (b *BrokerImpl) CancelAllOrders(symbol string) ([]string, error) {
+	return b.cli.CancelAllOrders(symbol)
+}
+
+func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
+	order, err := b.cli.GetOrder(orderId)
+	if err != nil {
+		return nil, err
+	}
+	orderData, err := b.orderData(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(orderData)
+}
+
+// GetOpenOrders возвращает активные ордера символа (пустой symbol - по всем символам)
+// в формате GetOrder
+func (b *BrokerImpl) GetOpenOrders(symbol string) ([]byte, error) {
+	openOrders, err := b.cli.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]map[string]any, 0, len(openOrders))
+	for i := range openOrders {
+		orderData, err := b.orderData(&openOrders[i])
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, orderData)
+	}
+
+	return json.Marshal(orders)
+}
+
+// orderData преобразует ордер в общий формат брокера. Ответ ордера не содержит комиссию,
+// поэтому она рассчитывается по сделкам ордера, но только после его закрытия:
+// стратегии опрашивают ордера, и запрос сделок при каждом опросе удваивал бы вес запросов.
+func (b *BrokerImpl) orderData(order *models.Order) (map[string]any, error) {
+	qty, parseErr := parseFloatOrZero(order.OrigQty)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	price, parseErr := parseFloatOrZero(order.Price)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	avgPrice, parseErr := parseFloatOrZero(order.AvgPrice)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	execQty, parseErr := parseFloatOrZero(order.ExecutedQty)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	execValue, parseErr := parseFloatOrZero(order.CumQuote)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	isClosed := true
+	switch order.Status {
+	case "NEW", "PARTIALLY_FILLED":
+		isClosed = false
+	}
+	var fee float64
+	if isClosed && execQty > 0 {
+		var err error
+		if fee, err = b.orderFee(order.Symbol, order.OrderId); err != nil {
+			return nil, err
+		}
+	}
+	if order.Side == "SELL" {
+		qty = -qty
+		execQty = -execQty
+		execValue = -execValue
+	}
+	orderData := map[string]any{
+		"id":        formatOrderId(order.Symbol, order.OrderId),
+		"symbol":    order.Symbol,
+		"qty":       qty,
+		"price":     price,
+		"avgPrice":  avgPrice,
+		"execQty":   execQty,
+		"execValue": execValue,
+		"fee":       fee,
+		"isClosed":  isClosed,
+		"createdAt": order.Time,
+		"updatedAt": order.UpdateTime,
+	}
+
+	return orderData, nil
+}
+
+var (
+	_ broker.Broker      = (*BrokerImpl)(nil)
+	_ cdl.CandleProvider = (*Client)(nil)
+)
+
+// orderFee возвращает комиссию ордера в котируемой монете. Комиссия списывается
+// в монете маржи или в BNB при оплате комиссий BNB, последняя пересчитывается по текущей цене.
+func (b *BrokerImpl) orderFee(symbol string, orderId int64) (float64, error) {
+	trades, err := b.cli.GetOrderTrades(symbol, orderId)
+	if err != nil {
+		return 0, err
+	}
+	var fee, bnbFee float64
+	for _, t := range trades {
+		commission, parseErr := parseFloatOrZero(t.Commission)
+		if parseErr != nil {
+			return 0, parseErr
+		}
+		if t.CommissionAsset == "BNB" {
+			bnbFee += commission
+		} else {
+			fee += commission
+		}
+	}
+	if bnbFee == 0 {
+		return fee, nil
+	}
+	info, err := b.cli.GetInstrumentInfo(symbol)
+	if err != nil {
+		return 0, err
+	}
+	bnbPrice, err := b.cli.GetPrice("BNB" + info.QuoteAsset)
+	if err != nil {
+		return 0, err
+	}
+
+	return fee + bnbFee*bnbPrice, nil
+}
@@ -0,0 +1,146 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/broker/binance/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+)
+
+// candlesLimit - максимальное количество свечей в одном запросе
+const candlesLimit = 1500
+
+// GetInstrumentInfo возвращает информацию о торговом инструменте по его символу.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Exchange-Information
+func (c *Client) GetInstrumentInfo(symbol string) (*models.SymbolInfo, error) {
+	var exchangeInfo models.ExchangeInfo
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/exchangeInfo", nil, false, &exchangeInfo); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetInstrumentInfo")
+	}
+	for i := range exchangeInfo.Symbols {
+		if exchangeInfo.Symbols[i].Symbol == symbol {
+			return &exchangeInfo.Symbols[i], nil
+		}
+	}
+	err := fmt.Errorf("symbol %s not found", symbol)
+
+	return nil, NewError(InternalErrorT, err).SetEndpoint("GetInstrumentInfo")
+}
+
+// GetPrice возвращает последнюю цену символа.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Symbol-Price-Ticker
+func (c *Client) GetPrice(symbol string) (float64, error) {
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	var tickerPrice models.TickerPrice
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/ticker/price", query, false, &tickerPrice); err != nil {
+		return 0, err.(*Error).SetEndpoint("GetPrice")
+	}
+	price, err := strconv.ParseFloat(tickerPrice.Price, 64)
+	if err != nil {
+		return 0, NewError(SerDeErrorT, err).SetEndpoint("GetPrice")
+	}
+
+	return price, nil
+}
+
+// GetCandles возвращает исторические свечи с ограничением по количеству. Последняя свеча не подтверждена.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/market-data/rest-api/Kline-Candlestick-Data
+func (c *Client) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	query.Set("interval", AsLocalInterval(interval))
+	query.Set("limit", strconv.Itoa(min(limit, candlesLimit)))
+	candles, err := c.getCandles(query)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := limit - candlesLimit
+	for counter > 0 && len(candles) > 0 {
+		nextLimit := min(candlesLimit, counter)
+		end := candles[0].Time - 1
+		query.Set("limit", strconv.Itoa(nextLimit))
+		query.Set("endTime", strconv.FormatInt(end, 10))
+		newCandles, err := c.getCandles(query)
+		if err != nil {
+			return candles, err
+		}
+		if len(newCandles) == 0 {
+			break
+		}
+		candles = append(newCandles, candles...)
+		counter -= nextLimit
+	}
+
+	return candles, nil
+}
+
+// getCandles запрашивает свечи в порядке возрастания времени
+func (c *Client) getCandles(query url.Values) ([]cdl.Candle, error) {
+	var klines [][]any
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/klines", query, false, &klines); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetCandles")
+	}
+	candles, err := extractCandles(klines)
+	if err != nil {
+		return nil, NewError(SerDeErrorT, err).SetEndpoint("GetCandles")
+	}
+
+	return candles, nil
+}
+
+// CandleStream подписывается на поток свечей через WebSocket.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/websocket-market-streams/Kline-Candlestick-Streams
+func (c *Client) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	streamURL := fmt.Sprintf(
+		"%s/ws/%s@kline_%s",
+		c.wsURL,
+		strings.ToLower(symbol),
+		AsLocalInterval(interval),
+	)
+	outChan, err := ws.Connect(streamURL, ctx)
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("CandleStream")
+	}
+
+	stream := make(chan *cdl.CandleStreamData)
+	go func() {
+		defer close(stream)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-outChan:
+				if !ok {
+					return
+				}
+				var klineRawData models.KlineStreamRawData
+				if err := json.Unmarshal(data, &klineRawData); err != nil || klineRawData.EventType != "kline" {
+					continue
+				}
+				candleStreamData, err := candleStreamFromRawData(&klineRawData, interval)
+				if err != nil {
+					continue
+				}
+				select {
+				case stream <- candleStreamData:
+				case <-time.After(time.Second):
+					if candleStreamData.Confirm {
+						stream <- candleStreamData
+					}
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
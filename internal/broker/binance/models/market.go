@@ -0,0 +1,69 @@
+package models
+
+// ExchangeInfo представляет ответ API с правилами торговли инструментами
+type ExchangeInfo struct {
+	ServerTime int64        `json:"serverTime"` // Время сервера (мс)
+	Symbols    []SymbolInfo `json:"symbols"`    // Инструменты
+}
+
+// SymbolInfo содержит параметры инструмента
+type SymbolInfo struct {
+	Symbol            string         `json:"symbol"`            // Название символа (торговая пара)
+	Pair              string         `json:"pair"`              // Базовая пара
+	ContractType      string         `json:"contractType"`      // Тип контракта: PERPETUAL, CURRENT_QUARTER, ...
+	Status            string         `json:"status"`            // Статус торговли: TRADING, ...
+	BaseAsset         string         `json:"baseAsset"`         // Базовая монета
+	QuoteAsset        string         `json:"quoteAsset"`        // Котируемая монета
+	MarginAsset       string         `json:"marginAsset"`       // Монета маржи
+	PricePrecision    int            `json:"pricePrecision"`    // Точность цены
+	QuantityPrecision int            `json:"quantityPrecision"` // Точность количества
+	Filters           []SymbolFilter `json:"filters"`           // Фильтры ордеров
+}
+
+// SymbolFilter содержит ограничения ордеров инструмента. Заполнены поля, относящиеся к FilterType.
+type SymbolFilter struct {
+	FilterType string `json:"filterType"` // Тип фильтра: PRICE_FILTER, LOT_SIZE, MIN_NOTIONAL, ...
+	TickSize   string `json:"tickSize"`   // Шаг цены (PRICE_FILTER)
+	MinPrice   string `json:"minPrice"`   // Минимальная цена (PRICE_FILTER)
+	MaxPrice   string `json:"maxPrice"`   // Максимальная цена (PRICE_FILTER)
+	StepSize   string `json:"stepSize"`   // Шаг количества (LOT_SIZE, MARKET_LOT_SIZE)
+	MinQty     string `json:"minQty"`     // Минимальное количество (LOT_SIZE, MARKET_LOT_SIZE)
+	MaxQty     string `json:"maxQty"`     // Максимальное количество (LOT_SIZE, MARKET_LOT_SIZE)
+	Notional   string `json:"notional"`   // Минимальная стоимость ордера (MIN_NOTIONAL)
+}
+
+// Filter возвращает фильтр инструмента по типу
+func (s *SymbolInfo) Filter(filterType string) (*SymbolFilter, bool) {
+	for i := range s.Filters {
+		if s.Filters[i].FilterType == filterType {
+			return &s.Filters[i], true
+		}
+	}
+	return nil, false
+}
+
+// TickerPrice содержит последнюю цену символа
+type TickerPrice struct {
+	Symbol string `json:"symbol"` // Название символа
+	Price  string `json:"price"`  // Последняя цена
+	Time   int64  `json:"time"`   // Время цены (мс)
+}
+
+// KlineStreamRawData представляет потоковые данные свечи
+type KlineStreamRawData struct {
+	EventType string `json:"e"` // Тип события: kline
+	EventTime int64  `json:"E"` // Время события (мс)
+	Symbol    string `json:"s"` // Название символа
+	Kline     struct {
+		StartTime   int64  `json:"t"` // Время открытия свечи (мс)
+		CloseTime   int64  `json:"T"` // Время закрытия свечи (мс)
+		Interval    string `json:"i"` // Интервал
+		Open        string `json:"o"` // Цена открытия
+		Close       string `json:"c"` // Цена закрытия
+		High        string `json:"h"` // Максимальная цена
+		Low         string `json:"l"` // Минимальная цена
+		Volume      string `json:"v"` // Объем в базовой монете
+		QuoteVolume string `json:"q"` // Оборот в котируемой монете
+		Closed      bool   `json:"x"` // Свеча закрыта
+	} `json:"k"`
+}
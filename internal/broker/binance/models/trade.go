@@ -0,0 +1,43 @@
+package models
+
+// Order содержит информацию об ордере
+type Order struct {
+	OrderId       int64  `json:"orderId"`       // ID ордера в системе Binance
+	ClientOrderId string `json:"clientOrderId"` // Пользовательский ID ордера
+	Symbol        string `json:"symbol"`        // Название символа (торговая пара)
+	Status        string `json:"status"`        // Статус: NEW, PARTIALLY_FILLED, FILLED, CANCELED, REJECTED, EXPIRED
+	Type          string `json:"type"`          // Тип ордера: MARKET, LIMIT, ...
+	Side          string `json:"side"`          // Направление сделки: BUY/SELL
+	TimeInForce   string `json:"timeInForce"`   // Условие времени действия ордера
+	Price         string `json:"price"`         // Цена ордера
+	AvgPrice      string `json:"avgPrice"`      // Средняя цена исполнения
+	OrigQty       string `json:"origQty"`       // Количество
+	ExecutedQty   string `json:"executedQty"`   // Исполненное количество
+	CumQuote      string `json:"cumQuote"`      // Исполненная стоимость в котируемой монете
+	ReduceOnly    bool   `json:"reduceOnly"`    // Флаг уменьшения позиции
+	Time          int64  `json:"time"`          // Время создания ордера (мс)
+	UpdateTime    int64  `json:"updateTime"`    // Время обновления ордера (мс)
+}
+
+// BatchOrderItem содержит результат операции над ордером пакета: ордер или ошибку
+type BatchOrderItem struct {
+	Order
+	Code int    `json:"code"` // Код ошибки (0 - успех)
+	Msg  string `json:"msg"`  // Описание ошибки
+}
+
+// UserTrade содержит информацию о сделке аккаунта
+type UserTrade struct {
+	Id              int64  `json:"id"`              // ID сделки
+	OrderId         int64  `json:"orderId"`         // ID ордера
+	Symbol          string `json:"symbol"`          // Название символа
+	Side            string `json:"side"`            // Направление сделки: BUY/SELL
+	Price           string `json:"price"`           // Цена
+	Qty             string `json:"qty"`             // Количество
+	QuoteQty        string `json:"quoteQty"`        // Стоимость
+	Commission      string `json:"commission"`      // Комиссия
+	CommissionAsset string `json:"commissionAsset"` // Монета комиссии
+	RealizedPnl     string `json:"realizedPnl"`     // Реализованная прибыль
+	Maker           bool   `json:"maker"`           // Сделка в роли мейкера
+	Time            int64  `json:"time"`            // Время сделки (мс)
+}
@@ -0,0 +1,241 @@
+package binance
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/binance/models"
+)
+
+const (
+	batchPlaceLimit  = 5  // максимальное количество ордеров в пакетном создании и изменении
+	batchCancelLimit = 10 // максимальное количество ордеров в пакетной отмене
+)
+
+// PlaceOrder создает рыночный или лимитный ордер и возвращает ID ордера вида "SYMBOL:orderId".
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/New-Order
+func (c *Client) PlaceOrder(symbol string, qty float64, price *float64) (string, error) {
+	query := make(url.Values)
+	for k, v := range placeOrderParams(symbol, qty, price) {
+		query.Set(k, v)
+	}
+	var order models.Order
+	if err := c.callAPI(http.MethodPost, "/fapi/v1/order", query, true, &order); err != nil {
+		return "", err.(*Error).SetEndpoint("PlaceOrder")
+	}
+
+	return formatOrderId(order.Symbol, order.OrderId), nil
+}
+
+// placeOrderParams формирует параметры создания рыночного или лимитного ордера
+func placeOrderParams(symbol string, qty float64, price *float64) map[string]string {
+	params := map[string]string{
+		"symbol":   symbol,
+		"side":     "BUY",
+		"type":     "MARKET",
+		"quantity": strconv.FormatFloat(math.Abs(qty), 'f', -1, 64),
+	}
+	if qty < 0 {
+		params["side"] = "SELL"
+	}
+	if price != nil {
+		params["type"] = "LIMIT"
+		params["timeInForce"] = "GTC"
+		params["price"] = strconv.FormatFloat(*price, 'f', -1, 64)
+	}
+
+	return params
+}
+
+// CancelOrder отменяет активный ордер по ID вида "SYMBOL:orderId".
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Cancel-Order
+func (c *Client) CancelOrder(orderId string) (string, error) {
+	symbol, id, err := parseOrderId(orderId)
+	if err != nil {
+		return "", err.(*Error).SetEndpoint("CancelOrder")
+	}
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	query.Set("orderId", id)
+	var order models.Order
+	if err := c.callAPI(http.MethodDelete, "/fapi/v1/order", query, true, &order); err != nil {
+		return "", err.(*Error).SetEndpoint("CancelOrder")
+	}
+
+	return formatOrderId(order.Symbol, order.OrderId), nil
+}
+
+// GetOrder возвращает ордер по ID вида "SYMBOL:orderId".
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Query-Order
+func (c *Client) GetOrder(orderId string) (*models.Order, error) {
+	symbol, id, err := parseOrderId(orderId)
+	if err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOrder")
+	}
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	query.Set("orderId", id)
+	var order models.Order
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/order", query, true, &order); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOrder")
+	}
+
+	return &order, nil
+}
+
+// GetOrderTrades возвращает сделки ордера. Используется для расчета комиссии,
+// которую ответ ордера не содержит.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Account-Trade-List
+func (c *Client) GetOrderTrades(symbol string, orderId int64) ([]models.UserTrade, error) {
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	query.Set("orderId", strconv.FormatInt(orderId, 10))
+	var trades []models.UserTrade
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/userTrades", query, true, &trades); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOrderTrades")
+	}
+
+	return trades, nil
+}
+
+// GetOpenOrders возвращает активные ордера символа (пустой symbol - по всем символам).
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Current-All-Open-Orders
+func (c *Client) GetOpenOrders(symbol string) ([]models.Order, error) {
+	query := make(url.Values)
+	if symbol != "" {
+		query.Set("symbol", symbol)
+	}
+	var orders []models.Order
+	if err := c.callAPI(http.MethodGet, "/fapi/v1/openOrders", query, true, &orders); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOpenOrders")
+	}
+
+	return orders, nil
+}
+
+// callBatchAPI выполняет пакетный запрос к /fapi/v1/batchOrders частями по limit элементов.
+// Параметры каждой части формируются функцией params. При ошибке запроса
+// возвращаются результаты уже выполненных частей.
+func (c *Client) callBatchAPI(endpoint, method string, n, limit int, params func(from, to int) (url.Values, error)) ([]broker.BatchResult, error) {
+	results := make([]broker.BatchResult, 0, n)
+	for i := 0; i < n; i += limit {
+		to := min(i+limit, n)
+		query, err := params(i, to)
+		if err != nil {
+			if e, ok := err.(*Error); ok {
+				return results, e.SetEndpoint(endpoint)
+			}
+			return results, NewError(SerDeErrorT, err).SetEndpoint(endpoint)
+		}
+		var items []models.BatchOrderItem
+		if err := c.callAPI(method, "/fapi/v1/batchOrders", query, true, &items); err != nil {
+			return results, err.(*Error).SetEndpoint(endpoint)
+		}
+
+		for k := range to - i {
+			var result broker.BatchResult
+			if k < len(items) {
+				if items[k].Code != 0 {
+					err := &serverResponseError{msg: items[k].Msg, code: items[k].Code}
+					result.Err = NewError(ServerResponseErrorT, err).SetEndpoint(endpoint)
+				} else {
+					result.OrderId = formatOrderId(items[k].Symbol, items[k].OrderId)
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// BatchPlaceOrders создает ордера пакетными запросами.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Place-Multiple-Orders
+func (c *Client) BatchPlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	return c.callBatchAPI("BatchPlaceOrders", http.MethodPost, len(orders), batchPlaceLimit, func(from, to int) (url.Values, error) {
+		items := make([]map[string]string, 0, to-from)
+		for _, o := range orders[from:to] {
+			items = append(items, placeOrderParams(o.Symbol, o.Qty, o.Price))
+		}
+		jsonData, err := json.Marshal(items)
+		return url.Values{"batchOrders": {string(jsonData)}}, err
+	})
+}
+
+// BatchAmendOrders изменяет ордера пакетными запросами. Binance требует указывать
+// направление, количество и цену, поэтому недостающие значения берутся из текущего ордера.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Modify-Multiple-Orders
+func (c *Client) BatchAmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	return c.callBatchAPI("BatchAmendOrders", http.MethodPut, len(orders), batchPlaceLimit, func(from, to int) (url.Values, error) {
+		items := make([]map[string]string, 0, to-from)
+		for _, o := range orders[from:to] {
+			order, err := c.GetOrder(o.OrderId)
+			if err != nil {
+				return nil, err
+			}
+			item := map[string]string{
+				"symbol":   order.Symbol,
+				"orderId":  strconv.FormatInt(order.OrderId, 10),
+				"side":     order.Side,
+				"quantity": order.OrigQty,
+				"price":    order.Price,
+			}
+			if o.Qty != nil {
+				item["quantity"] = strconv.FormatFloat(math.Abs(*o.Qty), 'f', -1, 64)
+			}
+			if o.Price != nil {
+				item["price"] = strconv.FormatFloat(*o.Price, 'f', -1, 64)
+			}
+			items = append(items, item)
+		}
+		jsonData, err := json.Marshal(items)
+		return url.Values{"batchOrders": {string(jsonData)}}, err
+	})
+}
+
+// BatchCancelOrders отменяет ордера символа пакетными запросами.
+// Принимаются ID вида "SYMBOL:orderId" и ID ордеров Binance.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Cancel-Multiple-Orders
+func (c *Client) BatchCancelOrders(symbol string, orderIds []string) ([]broker.BatchResult, error) {
+	return c.callBatchAPI("BatchCancelOrders", http.MethodDelete, len(orderIds), batchCancelLimit, func(from, to int) (url.Values, error) {
+		ids := make([]int64, 0, to-from)
+		for _, orderId := range orderIds[from:to] {
+			if _, id, err := parseOrderId(orderId); err == nil {
+				orderId = id
+			}
+			id, err := strconv.ParseInt(orderId, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		jsonData, err := json.Marshal(ids)
+		return url.Values{"symbol": {symbol}, "orderIdList": {string(jsonData)}}, err
+	})
+}
+
+// CancelAllOrders отменяет все активные ордера символа и возвращает ID отмененных ордеров.
+// Binance не возвращает отмененные ордера, поэтому они запрашиваются перед отменой.
+// https://developers.binance.com/docs/derivatives/usds-margined-futures/trade/rest-api/Cancel-All-Open-Orders
+func (c *Client) CancelAllOrders(symbol string) ([]string, error) {
+	orders, err := c.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, err.(*Error).SetEndpoint("CancelAllOrders")
+	}
+	query := make(url.Values)
+	query.Set("symbol", symbol)
+	if err := c.callAPI(http.MethodDelete, "/fapi/v1/allOpenOrders", query, true, nil); err != nil {
+		return nil, err.(*Error).SetEndpoint("CancelAllOrders")
+	}
+
+	orderIds := make([]string, len(orders))
+	for i, o := range orders {
+		orderIds[i] = formatOrderId(o.Symbol, o.OrderId)
+	}
+
+	return orderIds, nil
+}
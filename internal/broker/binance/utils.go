@@ -0,0 +1,123 @@
+package binance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikita55612/goTradingBot/internal/broker/binance/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+// AsLocalInterval преобразует cdl.Interval в локальный формат интервала.
+func AsLocalInterval(i cdl.Interval) string {
+	switch i {
+	case cdl.M1:
+		return "1m"
+	case cdl.M3:
+		return "3m"
+	case cdl.M5:
+		return "5m"
+	case cdl.M15:
+		return "15m"
+	case cdl.M30:
+		return "30m"
+	case cdl.H1:
+		return "1h"
+	case cdl.H2:
+		return "2h"
+	case cdl.H4:
+		return "4h"
+	case cdl.H6:
+		return "6h"
+	case cdl.H12:
+		return "12h"
+	case cdl.D1:
+		return "1d"
+	case cdl.D7:
+		return "1w"
+	case cdl.D30:
+		return "1M"
+	}
+	return ""
+}
+
+// candleStreamFromRawData преобразует сырые данные свечи из WebSocket в структурированный формат.
+// Время свечи потока - время ее закрытия, как и у остальных брокеров.
+func candleStreamFromRawData(d *models.KlineStreamRawData, interval cdl.Interval) (*cdl.CandleStreamData, error) {
+	k := d.Kline
+	rawData := [7]string{
+		strconv.FormatInt(k.CloseTime, 10),
+		k.Open,
+		k.High,
+		k.Low,
+		k.Close,
+		k.Volume,
+		k.QuoteVolume,
+	}
+	candle, err := cdl.ParseCandleFromRawData(rawData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cdl.CandleStreamData{
+		Interval: interval,
+		Confirm:  k.Closed,
+		Candle:   candle,
+	}, nil
+}
+
+// extractCandles преобразует массив сырых свечей вида
+// [openTime, open, high, low, close, volume, closeTime, quoteVolume, ...] в структурированные свечи
+func extractCandles(klines [][]any) ([]cdl.Candle, error) {
+	candles := make([]cdl.Candle, len(klines))
+
+	for i, v := range klines {
+		if len(v) < 8 {
+			return nil, fmt.Errorf("unexpected kline length: %d", len(v))
+		}
+		openTime, ok := v[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected kline open time: %v", v[0])
+		}
+		rawData := [7]string{strconv.FormatInt(int64(openTime), 10)}
+		for j, idx := range [6]int{1, 2, 3, 4, 5, 7} {
+			s, ok := v[idx].(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected kline value: %v", v[idx])
+			}
+			rawData[j+1] = s
+		}
+		candle, err := cdl.ParseCandleFromRawData(rawData)
+		if err != nil {
+			return nil, err
+		}
+		candles[i] = candle
+	}
+
+	return candles, nil
+}
+
+// parseFloatOrZero разбирает число, пустая строка соответствует нулю
+func parseFloatOrZero(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatOrderId формирует ID ордера брокера. ID ордера Binance уникален только
+// в пределах символа, поэтому символ включается в ID: "BTCUSDT:123".
+func formatOrderId(symbol string, orderId int64) string {
+	return fmt.Sprintf("%s:%d", symbol, orderId)
+}
+
+// parseOrderId разбирает ID ордера брокера на символ и ID ордера Binance
+func parseOrderId(orderId string) (string, string, error) {
+	symbol, id, ok := strings.Cut(orderId, ":")
+	if !ok || symbol == "" || id == "" {
+		return "", "", NewError(InternalErrorT, fmt.Errorf("invalid order id: %q", orderId))
+	}
+
+	return symbol, id, nil
+}
@@ -0,0 +1,133 @@
+package bybit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/httpx"
+)
+
+// batchLimit возвращает максимальное количество ордеров в одном пакетном запросе категории
+func (c *Client) batchLimit() int {
+	if c.category == "spot" {
+		return 10
+	}
+	return 20
+}
+
+// callBatchAPI выполняет пакетный запрос частями по batchLimit ордеров.
+// При ошибке запроса возвращаются результаты уже выполненных частей.
+func (c *Client) callBatchAPI(endpoint, path string, items []map[string]any) ([]broker.BatchResult, error) {
+	results := make([]broker.BatchResult, 0, len(items))
+	limit := c.batchLimit()
+	for i := 0; i < len(items); i += limit {
+		chunk := items[i:min(i+limit, len(items))]
+		for _, item := range chunk {
+			delete(item, "category")
+		}
+		jsonData, _ := json.Marshal(map[string]any{
+			"category": c.category,
+			"request":  chunk,
+		})
+		req := httpx.Post(fmt.Sprintf("%s%s", c.baseURL, path)).WithData(jsonData)
+		var batchOrderResult models.BatchOrderResult
+		var batchExtInfo models.BatchExtInfo
+		if err := c.callAPIExt(req, string(jsonData), &batchOrderResult, &batchExtInfo); err != nil {
+			return results, err.(*Error).SetEndpoint(endpoint)
+		}
+
+		for k := range chunk {
+			var result broker.BatchResult
+			if k < len(batchOrderResult.List) {
+				result.OrderId = batchOrderResult.List[k].OrderId
+			}
+			if k < len(batchExtInfo.List) && batchExtInfo.List[k].Code != 0 {
+				err := &serverResponseError{msg: batchExtInfo.List[k].Msg, code: batchExtInfo.List[k].Code}
+				result.Err = NewError(ServerResponseErrorT, err).SetEndpoint(endpoint)
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// BatchPlaceOrders создает ордера пакетными запросами.
+// https://bybit-exchange.github.io/docs/v5/order/batch-place
+func (c *Client) BatchPlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	items := make([]map[string]any, len(orders))
+	for i, o := range orders {
+		items[i] = c.placeOrderParams(o.Symbol, o.Qty, o.Price)
+	}
+	results, err := c.callBatchAPI("BatchPlaceOrders", "/v5/order/create-batch", items)
+	if c.tradeWS != nil {
+		for i, r := range results {
+			if r.Err == nil && r.OrderId != "" {
+				c.tradeWS.rememberOrder(r.OrderId, orders[i].Symbol)
+			}
+		}
+	}
+
+	return results, err
+}
+
+// BatchAmendOrders изменяет ордера пакетными запросами.
+// https://bybit-exchange.github.io/docs/v5/order/batch-amend
+func (c *Client) BatchAmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	items := make([]map[string]any, len(orders))
+	for i, o := range orders {
+		items[i] = c.amendOrderParams(o.Symbol, o.OrderId, o.Qty, o.Price)
+	}
+
+	return c.callBatchAPI("BatchAmendOrders", "/v5/order/amend-batch", items)
+}
+
+// BatchCancelOrders отменяет ордера символа пакетными запросами.
+// https://bybit-exchange.github.io/docs/v5/order/batch-cancel
+func (c *Client) BatchCancelOrders(symbol string, orderIds []string) ([]broker.BatchResult, error) {
+	items := make([]map[string]any, len(orderIds))
+	for i, id := range orderIds {
+		items[i] = map[string]any{
+			"symbol":  symbol,
+			"orderId": id,
+		}
+	}
+
+	results, err := c.callBatchAPI("BatchCancelOrders", "/v5/order/cancel-batch", items)
+	if c.tradeWS != nil {
+		for _, r := range results {
+			if r.Err == nil {
+				c.tradeWS.forgetOrder(r.OrderId)
+			}
+		}
+	}
+
+	return results, err
+}
+
+// CancelAllOrders отменяет все активные ордера символа и возвращает ID отмененных ордеров.
+// https://bybit-exchange.github.io/docs/v5/order/cancel-all
+func (c *Client) CancelAllOrders(symbol string) ([]string, error) {
+	jsonData, _ := json.Marshal(map[string]any{
+		"category": c.category,
+		"symbol":   symbol,
+	})
+	path := fmt.Sprintf("%s%s", c.baseURL, "/v5/order/cancel-all")
+	req := httpx.Post(path).WithData(jsonData)
+	var cancelAllOrdersResult models.CancelAllOrdersResult
+	if err := c.callAPI(req, string(jsonData), &cancelAllOrdersResult); err != nil {
+		return nil, err.(*Error).SetEndpoint("CancelAllOrders")
+	}
+
+	orderIds := make([]string, len(cancelAllOrdersResult.List))
+	for i, o := range cancelAllOrdersResult.List {
+		orderIds[i] = o.OrderId
+		if c.tradeWS != nil {
+			c.tradeWS.forgetOrder(o.OrderId)
+		}
+	}
+
+	return orderIds, nil
+}
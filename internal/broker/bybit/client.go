@@ -17,19 +17,21 @@ import (
 )
 
 const (
-	PUBLICWS   = "wss://stream.bybit.com/v5/public"
-	MAINNET    = "https://api.bybit.com"
-	MAINNETALT = "https://api.bytick.com"
-	TESTNET    = "https://api-testnet.bybit.com"
+	PUBLICWS        = "wss://stream.bybit.com/v5/public"
+	PUBLICWSTESTNET = "wss://stream-testnet.bybit.com/v5/public"
+	MAINNET         = "https://api.bybit.com"
+	MAINNETALT      = "https://api.bytick.com"
+	TESTNET         = "https://api-testnet.bybit.com"
+	DEMO            = "https://api-demo.bybit.com"
 )
 
 // ServerResponse представляет структуру стандартного ответа от API Bybit
 type ServerResponse struct {
-	RetCode    int      `json:"retCode"`    // RetCode - код возврата, где 0 означает успешный запрос
-	RetMsg     string   `json:"retMsg"`     // RetMsg - сообщение от сервера ("OK", "SUCCESS" или описание ошибки)
-	Result     any      `json:"result"`     // Result - основные данные ответа, тип зависит от конкретного запроса
-	RetExtInfo struct{} `json:"retExtInfo"` // RetExtInfo - дополнительная информация (обычно пустой объект)
-	Time       int64    `json:"time"`       // Time - временная метка сервера в миллисекундах
+	RetCode    int             `json:"retCode"`    // RetCode - код возврата, где 0 означает успешный запрос
+	RetMsg     string          `json:"retMsg"`     // RetMsg - сообщение от сервера ("OK", "SUCCESS" или описание ошибки)
+	Result     any             `json:"result"`     // Result - основные данные ответа, тип зависит от конкретного запроса
+	RetExtInfo json.RawMessage `json:"retExtInfo"` // RetExtInfo - дополнительная информация (результаты пакетных запросов)
+	Time       int64           `json:"time"`       // Time - временная метка сервера в миллисекундах
 }
 
 // Client представляет клиент для работы с REST API Bybit
@@ -41,6 +43,8 @@ type Client struct {
 	category   string          // spot/linear/inverse
 	ctx        context.Context // контекст для выполнения запросов
 	timeout    time.Duration   // таймаут HTTP-запросов
+	publicWS   string          // базовый URL публичных WebSocket потоков
+	tradeWS    *tradeWS        // WebSocket API ордеров (nil - только REST)
 }
 
 // NewClient создает новый экземпляр клиента для работы с API Bybit
@@ -49,6 +53,7 @@ type Client struct {
 func NewClient(apiKey, apiSecret string, opts ...Option) *Client {
 	client := &Client{
 		baseURL:    MAINNET,
+		publicWS:   PUBLICWS,
 		apiKey:     apiKey,
 		apiSecret:  apiSecret,
 		recvWindow: 5000,
@@ -108,6 +113,13 @@ func WithBaseURL(url string) Option {
 	}
 }
 
+// WithPublicWS устанавливает базовый URL публичных WebSocket потоков (PUBLICWS или PUBLICWSTESTNET)
+func WithPublicWS(url string) Option {
+	return func(c *Client) {
+		c.publicWS = url
+	}
+}
+
 // WithCategory устанавливает категорию (spot, linear, inverse)
 func WithCategory(category string) Option {
 	return func(c *Client) {
@@ -116,6 +128,11 @@ func WithCategory(category string) Option {
 }
 
 func (c *Client) callAPI(req httpx.RequestBuilder, queryString string, result any) error {
+	return c.callAPIExt(req, queryString, result, nil)
+}
+
+// callAPIExt выполняет запрос как callAPI и дополнительно разбирает retExtInfo в retExtInfo
+func (c *Client) callAPIExt(req httpx.RequestBuilder, queryString string, result, retExtInfo any) error {
 	timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
 	signature := fmt.Sprintf("%s%s%d%s", timestamp, c.apiKey, c.recvWindow, queryString)
 	mac := hmac.New(sha256.New, []byte(c.apiSecret))
@@ -160,6 +177,11 @@ func (c *Client) callAPI(req httpx.RequestBuilder, queryString string, result an
 			return NewError(SerDeErrorT, err)
 		}
 	}
+	if retExtInfo != nil && len(serverResponse.RetExtInfo) > 0 {
+		if err := json.Unmarshal(serverResponse.RetExtInfo, retExtInfo); err != nil {
+			return NewError(SerDeErrorT, err)
+		}
+	}
 
 	return nil
 }
@@ -3,10 +3,15 @@ package bybit
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/book"
 	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/tick"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ticker"
 	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
 )
 
@@ -27,7 +32,7 @@ func (b *BrokerImpl) GetInstrumentInfo(symbol string) ([]byte, error) {
 	var minOrderAmt float64
 	var qtyPrecision int
 	if b.cli.category == "spot" {
-		v, parseErr := strconv.ParseFloat(info.LotSizeFilter.MaxOrderAmt, 64)
+		v, parseErr := strconv.ParseFloat(info.LotSizeFilter.MinOrderAmt, 64)
 		if parseErr != nil {
 			return nil, parseErr
 		}
@@ -58,11 +63,47 @@ func (b *BrokerImpl) GetInstrumentInfo(symbol string) ([]byte, error) {
 		"qtyPrecision": qtyPrecision,
 		"minOrderAmt":  minOrderAmt,
 		"tickSize":     tickSize,
+		"category":     b.cli.category,
 	}
 
 	return json.Marshal(infoData)
 }
 
+func (b *BrokerImpl) GetFundingInfo(symbol string) ([]byte, error) {
+	if b.cli.category == "spot" {
+		return nil, fmt.Errorf("funding is not available for spot instruments")
+	}
+	ticker, err := b.cli.GetTicker(symbol)
+	if err != nil {
+		return nil, err
+	}
+	info, err := b.cli.GetInstrumentInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	fundingRate, parseErr := strconv.ParseFloat(ticker.FundingRate, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	nextFundingTime, parseErr := strconv.ParseInt(ticker.NextFundingTime, 10, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	markPrice, parseErr := strconv.ParseFloat(ticker.MarkPrice, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	fundingData := map[string]any{
+		"fundingRate":     fundingRate,
+		"nextFundingTime": nextFundingTime,
+		"fundingInterval": info.FundingInterval,
+		"markPrice":       markPrice,
+	}
+
+	return json.Marshal(fundingData)
+}
+
 func (b *BrokerImpl) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
 	return b.cli.GetCandles(symbol, interval, limit)
 }
@@ -71,6 +112,22 @@ func (b *BrokerImpl) CandleStream(ctx context.Context, symbol string, interval c
 	return b.cli.CandleStream(ctx, symbol, interval)
 }
 
+func (b *BrokerImpl) StreamReconnects(ctx context.Context) (<-chan struct{}, error) {
+	return b.cli.StreamReconnects(ctx)
+}
+
+func (b *BrokerImpl) OrderBookStream(ctx context.Context, symbol string, depth int) (<-chan *book.Update, error) {
+	return b.cli.OrderBookStream(ctx, symbol, depth)
+}
+
+func (b *BrokerImpl) TradeStream(ctx context.Context, symbol string) (<-chan *tick.Tick, error) {
+	return b.cli.TradeStream(ctx, symbol)
+}
+
+func (b *BrokerImpl) TickerStream(ctx context.Context, symbol string) (<-chan *ticker.Ticker, error) {
+	return b.cli.TickerStream(ctx, symbol)
+}
+
 func (b *BrokerImpl) PlaceOrder(symbol string, qty float64, price *float64) (string, error) {
 	return b.cli.PlaceOrder(symbol, qty, price)
 }
@@ -79,11 +136,56 @@ func (b *BrokerImpl) CancelOrder(orderId string) (string, error) {
 	return b.cli.CancelOrder(orderId)
 }
 
+func (b *BrokerImpl) PlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchPlaceOrders(orders)
+}
+
+func (b *BrokerImpl) AmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchAmendOrders(orders)
+}
+
+func (b *BrokerImpl) CancelOrders(symbol string, orderIds []string) ([]broker.BatchResult, error) {
+	return b.cli.BatchCancelOrders(symbol, orderIds)
+}
+
+func (b *BrokerImpl) CancelAllOrders(symbol string) ([]string, error) {
+	return b.cli.CancelAllOrders(symbol)
+}
+
 func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
 	detail, err := b.cli.GetOrderHistoryDetail(orderId)
 	if err != nil {
 		return nil, err
 	}
+	orderData, parseErr := b.orderData(detail)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return json.Marshal(orderData)
+}
+
+// GetOpenOrders возвращает активные ордера символа (пустой symbol - все символы категории)
+// в формате GetOrder
+func (b *BrokerImpl) GetOpenOrders(symbol string) ([]byte, error) {
+	details, err := b.cli.GetAllOpenOrders(OrderFilter{Symbol: symbol})
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]map[string]any, 0, len(details))
+	for i := range details {
+		orderData, err := b.orderData(&details[i])
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, orderData)
+	}
+
+	return json.Marshal(orders)
+}
+
+// orderData преобразует детали ордера в общий формат брокера
+func (b *BrokerImpl) orderData(detail *models.OrderHistoryDetail) (map[string]any, error) {
 	createdAt, parseErr := strconv.ParseInt(detail.CreatedTime, 10, 64)
 	if parseErr != nil {
 		return nil, parseErr
@@ -92,23 +194,23 @@ func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	qty, parseErr := strconv.ParseFloat(detail.Qty, 64)
+	qty, parseErr := parseFloatOrZero(detail.Qty)
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	price, parseErr := strconv.ParseFloat(detail.Price, 64)
+	price, parseErr := parseFloatOrZero(detail.Price)
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	avgPrice, parseErr := strconv.ParseFloat(detail.AvgPrice, 64)
+	avgPrice, parseErr := parseFloatOrZero(detail.AvgPrice)
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	execQty, parseErr := strconv.ParseFloat(detail.CumExecQty, 64)
+	execQty, parseErr := parseFloatOrZero(detail.CumExecQty)
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	execValue, parseErr := strconv.ParseFloat(detail.CumExecValue, 64)
+	execValue, parseErr := parseFloatOrZero(detail.CumExecValue)
 	if parseErr != nil {
 		return nil, parseErr
 	}
@@ -117,15 +219,15 @@ func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
 		execQty = -execQty
 		execValue = -execValue
 	}
-	fee, parseErr := strconv.ParseFloat(detail.CumExecFee, 64)
+	fee, parseErr := parseFloatOrZero(detail.CumExecFee)
 	if parseErr != nil {
 		return nil, parseErr
 	}
-	isClosed := true
-	switch detail.OrderStatus {
-	case "New", "PartiallyFilled", "Untriggered":
-		isClosed = false
+	if b.cli.category == "spot" && detail.Side == "Buy" {
+		// Комиссия спотовой покупки списывается в базовой монете
+		fee *= avgPrice
 	}
+	isClosed := isOrderClosed(detail.OrderStatus)
 	orderData := map[string]any{
 		"id":        detail.OrderId,
 		"symbol":    detail.Symbol,
@@ -140,5 +242,5 @@ func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
 		"updatedAt": updatedAt,
 	}
 
-	return json.Marshal(orderData)
+	return orderData, nil
 }
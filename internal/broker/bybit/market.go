@@ -9,10 +9,11 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/book"
 	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
-	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+	"github.com/nikita55612/goTradingBot/internal/pkg/tick"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ticker"
 	"github.com/nikita55612/httpx"
 )
 
@@ -85,23 +86,12 @@ func (c *Client) GetCandles(symbol string, interval cdl.Interval, limit int) ([]
 	return candles, nil
 }
 
-// CandleStream устанавливает WebSocket соединение для потокового получения свечей.
+// CandleStream подписывается на поток свечей через общее публичное WebSocket соединение категории.
 // https://bybit-exchange.github.io/docs/v5/websocket/public/kline
 func (c *Client) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
 	arg := fmt.Sprintf("kline.%s.%s", AsLocalInterval(interval), symbol)
-	subMessage := map[string]any{
-		"req_id": uuid.NewString(),
-		"op":     "subscribe",
-		"args":   []string{arg},
-	}
-	handshakeMessage, _ := json.Marshal(subMessage)
-	outChan, err := ws.Connect(
-		fmt.Sprintf("%s/%s", PUBLICWS, c.category),
-		ctx,
-		ws.WithHandshake(handshakeMessage),
-	)
+	_, outChan, err := c.publicStream(ctx, arg)
 	if err != nil {
-		err = fmt.Errorf("failed to create websocket connection: %w", err)
 		return nil, NewError(InternalErrorT, err).SetEndpoint("CandleStream")
 	}
 
@@ -112,7 +102,11 @@ func (c *Client) CandleStream(ctx context.Context, symbol string, interval cdl.I
 			case <-ctx.Done():
 				close(stream)
 				return
-			case data := <-outChan:
+			case data, ok := <-outChan:
+				if !ok {
+					close(stream)
+					return
+				}
 				var candleStreamRawData models.CandleStreamRawData
 				if err := json.Unmarshal(data, &candleStreamRawData); err != nil {
 					continue
@@ -136,6 +130,194 @@ func (c *Client) CandleStream(ctx context.Context, symbol string, interval cdl.I
 	return stream, nil
 }
 
+// orderBookDepths - допустимые глубины потока книги ордеров
+var orderBookDepths = []int{1, 50, 200, 500, 1000}
+
+// OrderBookStream подписывается на поток книги ордеров через общее публичное WebSocket соединение.
+// Изменения проверяются по идентификатору обновления: при разрыве последовательности
+// выполняется повторная подписка, и биржа присылает новый снимок.
+// https://bybit-exchange.github.io/docs/v5/websocket/public/orderbook
+func (c *Client) OrderBookStream(ctx context.Context, symbol string, depth int) (<-chan *book.Update, error) {
+	if !slices.Contains(orderBookDepths, depth) {
+		err := fmt.Errorf("unsupported order book depth: %d", depth)
+		return nil, NewError(InternalErrorT, err).SetEndpoint("OrderBookStream")
+	}
+
+	arg := fmt.Sprintf("orderbook.%d.%s", depth, symbol)
+	mux, outChan, err := c.publicStream(ctx, arg)
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("OrderBookStream")
+	}
+
+	stream := make(chan *book.Update)
+	go func() {
+		defer close(stream)
+
+		var lastUpdateID int64
+		for {
+			var data []byte
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-outChan:
+				if !ok {
+					return
+				}
+				data = d
+			}
+
+			var orderBookRawData models.OrderBookStreamRawData
+			if err := json.Unmarshal(data, &orderBookRawData); err != nil {
+				continue
+			}
+			update, err := orderBookUpdateFromRawData(&orderBookRawData)
+			if err != nil {
+				continue
+			}
+
+			if !update.Snapshot {
+				if lastUpdateID == 0 {
+					continue
+				}
+				if update.UpdateID != lastUpdateID+1 {
+					// Разрыв последовательности: переподписываемся за новым снимком
+					lastUpdateID = 0
+					mux.resubscribe(arg)
+					continue
+				}
+			}
+			lastUpdateID = update.UpdateID
+
+			select {
+			case stream <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// TradeStream подписывается на поток публичных сделок через общее публичное WebSocket соединение.
+// https://bybit-exchange.github.io/docs/v5/websocket/public/trade
+func (c *Client) TradeStream(ctx context.Context, symbol string) (<-chan *tick.Tick, error) {
+	arg := fmt.Sprintf("publicTrade.%s", symbol)
+	_, outChan, err := c.publicStream(ctx, arg)
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("TradeStream")
+	}
+
+	stream := make(chan *tick.Tick, 64)
+	go func() {
+		defer close(stream)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-outChan:
+				if !ok {
+					return
+				}
+				var publicTradeRawData models.PublicTradeStreamRawData
+				if err := json.Unmarshal(data, &publicTradeRawData); err != nil {
+					continue
+				}
+				ticks, err := ticksFromRawData(&publicTradeRawData)
+				if err != nil {
+					continue
+				}
+				for _, t := range ticks {
+					select {
+					case stream <- t:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// TickerStream подписывается на поток тикера через общее публичное WebSocket соединение.
+// Изменения накладываются на последний снимок, поэтому каждое значение потока - полный тикер.
+// https://bybit-exchange.github.io/docs/v5/websocket/public/ticker
+func (c *Client) TickerStream(ctx context.Context, symbol string) (<-chan *ticker.Ticker, error) {
+	arg := fmt.Sprintf("tickers.%s", symbol)
+	_, outChan, err := c.publicStream(ctx, arg)
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("TickerStream")
+	}
+
+	stream := make(chan *ticker.Ticker)
+	go func() {
+		defer close(stream)
+
+		var current models.Ticker
+		var hasSnapshot bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-outChan:
+				if !ok {
+					return
+				}
+				var tickerRawData models.TickerStreamRawData
+				if err := json.Unmarshal(data, &tickerRawData); err != nil {
+					continue
+				}
+				if tickerRawData.Type == "snapshot" {
+					current, hasSnapshot = models.Ticker{}, true
+				} else if !hasSnapshot {
+					continue
+				}
+				// Изменение содержит только обновленные поля
+				if err := json.Unmarshal(tickerRawData.Data, &current); err != nil {
+					continue
+				}
+				t := tickerFromRawData(&current)
+				t.Time = tickerRawData.Ts
+				select {
+				case stream <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// GetTicker возвращает снимок тикера, включая прогнозную ставку ближайшего фандинга.
+// https://bybit-exchange.github.io/docs/v5/market/tickers
+func (c *Client) GetTicker(symbol string) (*models.Ticker, error) {
+	query := make(url.Values)
+	query.Set("category", c.category)
+	query.Set("symbol", symbol)
+	queryString := query.Encode()
+	path := fmt.Sprintf(
+		"%s%s?%s",
+		c.baseURL,
+		"/v5/market/tickers",
+		queryString,
+	)
+	req := httpx.Get(path)
+	var tickersResult models.TickersResult
+	if err := c.callAPI(req, queryString, &tickersResult); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetTicker")
+	}
+	if len(tickersResult.List) == 0 {
+		err := fmt.Errorf("ticker %s not found", symbol)
+		return nil, NewError(InternalErrorT, err).SetEndpoint("GetTicker")
+	}
+
+	return &tickersResult.List[0], nil
+}
+
 // getCandles выполняет запрос исторических данных свечей
 func (c *Client) getCandle(query url.Values) (*models.CandleResult, *Error) {
 	queryString := query.Encode()
@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // CandleResult представляет данные свечи за определенный период времени.
 type CandleResult struct {
 	Category string      `json:"category"` // тип продукта (например, "inverse" - обратный контракт)
@@ -101,3 +103,81 @@ type CandleStreamRawData struct {
 		Confirm   bool   `json:"confirm"`   // Подтверждение
 	} `json:"data"`
 }
+
+// TickersResult представляет ответ API со снимками тикеров
+type TickersResult struct {
+	Category string   `json:"category"` // Категория инструментов (spot, linear, inverse)
+	List     []Ticker `json:"list"`     // Список тикеров
+}
+
+// Ticker представляет снимок рыночных данных инструмента
+type Ticker struct {
+	Symbol                 string `json:"symbol"`                 // Название торговой пары
+	LastPrice              string `json:"lastPrice"`              // Цена последней сделки
+	IndexPrice             string `json:"indexPrice"`             // Индексная цена
+	MarkPrice              string `json:"markPrice"`              // Маркировочная цена
+	PrevPrice24h           string `json:"prevPrice24h"`           // Цена 24 часа назад
+	Price24hPcnt           string `json:"price24hPcnt"`           // Изменение цены за 24 часа (доля)
+	HighPrice24h           string `json:"highPrice24h"`           // Максимальная цена за 24 часа
+	LowPrice24h            string `json:"lowPrice24h"`            // Минимальная цена за 24 часа
+	PrevPrice1h            string `json:"prevPrice1h"`            // Цена час назад
+	OpenInterest           string `json:"openInterest"`           // Открытый интерес (в базовой монете)
+	OpenInterestValue      string `json:"openInterestValue"`      // Стоимость открытого интереса
+	Turnover24h            string `json:"turnover24h"`            // Оборот за 24 часа
+	Volume24h              string `json:"volume24h"`              // Объем за 24 часа
+	FundingRate            string `json:"fundingRate"`            // Прогнозная ставка ближайшего фандинга
+	NextFundingTime        string `json:"nextFundingTime"`        // Время ближайшего фандинга (мс)
+	PredictedDeliveryPrice string `json:"predictedDeliveryPrice"` // Прогнозная цена поставки
+	BasisRate              string `json:"basisRate"`              // Ставка базиса
+	Basis                  string `json:"basis"`                  // Базис
+	DeliveryFeeRate        string `json:"deliveryFeeRate"`        // Комиссия за поставку
+	DeliveryTime           string `json:"deliveryTime"`           // Время поставки (мс)
+	Bid1Price              string `json:"bid1Price"`              // Лучшая цена покупки
+	Bid1Size               string `json:"bid1Size"`               // Объем лучшей цены покупки
+	Ask1Price              string `json:"ask1Price"`              // Лучшая цена продажи
+	Ask1Size               string `json:"ask1Size"`               // Объем лучшей цены продажи
+}
+
+// OrderBookStreamRawData представляет потоковые данные книги ордеров
+type OrderBookStreamRawData struct {
+	Topic string `json:"topic"` // Топик подписки
+	Type  string `json:"type"`  // Тип сообщения (snapshot, delta)
+	Ts    int64  `json:"ts"`    // Время формирования данных системой (мс)
+	Cts   int64  `json:"cts"`   // Время сопоставления, соответствующее данным (мс)
+
+	Data struct {
+		Symbol   string      `json:"s"`   // Название торговой пары
+		Bids     [][2]string `json:"b"`   // Заявки на покупку [цена, размер], размер 0 - удаление уровня
+		Asks     [][2]string `json:"a"`   // Заявки на продажу [цена, размер], размер 0 - удаление уровня
+		UpdateID int64       `json:"u"`   // Идентификатор обновления, u=1 - снимок после перезапуска сервиса
+		Seq      int64       `json:"seq"` // Кросс-последовательность
+	} `json:"data"`
+}
+
+// PublicTradeStreamRawData представляет потоковые данные публичных сделок
+type PublicTradeStreamRawData struct {
+	Topic string `json:"topic"` // Топик подписки
+	Type  string `json:"type"`  // Тип сообщения
+	Ts    int64  `json:"ts"`    // Время формирования данных системой (мс)
+
+	Data []struct {
+		Time       int64  `json:"T"`  // Время сделки (мс)
+		Symbol     string `json:"s"`  // Название торговой пары
+		Side       string `json:"S"`  // Сторона агрессора (Buy, Sell)
+		Size       string `json:"v"`  // Объем сделки
+		Price      string `json:"p"`  // Цена сделки
+		Direction  string `json:"L"`  // Направление изменения цены
+		ID         string `json:"i"`  // Идентификатор сделки
+		BlockTrade bool   `json:"BT"` // Блочная сделка
+	} `json:"data"`
+}
+
+// TickerStreamRawData представляет потоковые данные тикера.
+// Для деривативов после снимка приходят изменения только с обновленными полями.
+type TickerStreamRawData struct {
+	Topic string          `json:"topic"` // Топик подписки
+	Type  string          `json:"type"`  // Тип сообщения (snapshot, delta)
+	Ts    int64           `json:"ts"`    // Время формирования данных системой (мс)
+	Cs    int64           `json:"cs"`    // Кросс-последовательность
+	Data  json.RawMessage `json:"data"`  // Поля Ticker
+}
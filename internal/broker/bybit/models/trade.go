@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // OrderResult содержит ответ API на создание ордера
 type PlaceOrderResult struct {
 	OrderId     string `json:"orderId"`     // ID ордера в системе Bybit
@@ -12,6 +14,49 @@ type CancelOrderResult struct {
 	OrderLinkId string `json:"orderLinkId"` // Пользовательский ID ордера (если был указан)
 }
 
+// BatchOrderResult содержит ответ API на пакетную операцию с ордерами
+type BatchOrderResult struct {
+	List []BatchOrderItem `json:"list"` // Результаты в порядке запроса
+}
+
+// BatchOrderItem содержит результат операции над ордером пакета
+type BatchOrderItem struct {
+	Category    string `json:"category"`    // Тип продукта (категория)
+	Symbol      string `json:"symbol"`      // Название символа (торговая пара)
+	OrderId     string `json:"orderId"`     // ID ордера в системе Bybit
+	OrderLinkId string `json:"orderLinkId"` // Пользовательский ID ордера (если был указан)
+}
+
+// BatchExtInfo содержит статусы операций над ордерами пакета
+type BatchExtInfo struct {
+	List []struct {
+		Code int    `json:"code"` // Код возврата, где 0 означает успех
+		Msg  string `json:"msg"`  // Сообщение от сервера
+	} `json:"list"`
+}
+
+// CancelAllOrdersResult содержит ответ API на отмену всех ордеров
+type CancelAllOrdersResult struct {
+	List    []CancelOrderResult `json:"list"`    // Отмененные ордера
+	Success string              `json:"success"` // "1" - успешно (spot)
+}
+
+// AmendOrderResult содержит ответ API на изменение ордера
+type AmendOrderResult struct {
+	OrderId     string `json:"orderId"`     // ID ордера в системе Bybit
+	OrderLinkId string `json:"orderLinkId"` // Пользовательский ID ордера (если был указан)
+}
+
+// TradeStreamResponse представляет ответ WebSocket API на операцию с ордером
+type TradeStreamResponse struct {
+	ReqId   string          `json:"reqId"`   // ID запроса
+	RetCode int             `json:"retCode"` // Код возврата, где 0 означает успешный запрос
+	RetMsg  string          `json:"retMsg"`  // Сообщение от сервера
+	Op      string          `json:"op"`      // Операция: auth, order.create, order.amend, order.cancel
+	Data    json.RawMessage `json:"data"`    // Результат операции
+	ConnId  string          `json:"connId"`  // ID соединения
+}
+
 // OrderHistoryResult представляет ответ API со списком ордеров
 type OrderHistoryResult struct {
 	List           []OrderHistoryDetail `json:"list"`           // Список ордеров
@@ -65,3 +110,37 @@ type OrderHistoryDetail struct {
 	CreatedTime           string `json:"createdTime"`           // Время создания ордера (мс)
 	UpdatedTime           string `json:"updatedTime"`           // Время обновления ордера (мс)
 }
+
+// ExecutionResult представляет ответ API со списком исполнений
+type ExecutionResult struct {
+	List           []Execution `json:"list"`           // Список исполнений
+	NextPageCursor string      `json:"nextPageCursor"` // Курсор для пагинации (токен следующей страницы)
+	Category       string      `json:"category"`       // Тип продукта (категория)
+}
+
+// Execution содержит информацию об исполнении (сделке) по ордеру
+type Execution struct {
+	Symbol        string `json:"symbol"`        // Название символа (торговая пара)
+	OrderId       string `json:"orderId"`       // ID ордера в системе Bybit
+	OrderLinkId   string `json:"orderLinkId"`   // Пользовательский ID ордера
+	Side          string `json:"side"`          // Направление сделки: Buy/Sell
+	OrderPrice    string `json:"orderPrice"`    // Цена ордера
+	OrderQty      string `json:"orderQty"`      // Количество ордера
+	LeavesQty     string `json:"leavesQty"`     // Оставшееся количество для исполнения
+	CreateType    string `json:"createType"`    // Способ создания ордера
+	OrderType     string `json:"orderType"`     // Тип ордера: Market/Limit
+	StopOrderType string `json:"stopOrderType"` // Тип стоп-ордера
+	ExecFee       string `json:"execFee"`       // Комиссия исполнения
+	ExecId        string `json:"execId"`        // ID исполнения
+	ExecPrice     string `json:"execPrice"`     // Цена исполнения
+	ExecQty       string `json:"execQty"`       // Исполненное количество
+	ExecType      string `json:"execType"`      // Тип исполнения: Trade/Funding/...
+	ExecValue     string `json:"execValue"`     // Стоимость исполнения
+	ExecTime      string `json:"execTime"`      // Время исполнения (мс)
+	FeeCurrency   string `json:"feeCurrency"`   // Валюта комиссии (spot)
+	IsMaker       bool   `json:"isMaker"`       // Исполнение в роли мейкера
+	FeeRate       string `json:"feeRate"`       // Ставка комиссии
+	MarkPrice     string `json:"markPrice"`     // Маркировочная цена на момент исполнения
+	ClosedSize    string `json:"closedSize"`    // Закрытый объем позиции
+	Seq           int64  `json:"seq"`           // Кросс-последовательность
+}
@@ -0,0 +1,180 @@
+package bybit
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/httpx"
+)
+
+const (
+	ordersPageLimit     = 50                 // Максимальный размер страницы ордеров
+	executionsPageLimit = 100                // Максимальный размер страницы исполнений
+	historyWindow       = 7 * 24 * time.Hour // Максимальный диапазон времени одного запроса истории
+)
+
+// OrderFilter - параметры запросов ордеров и исполнений
+type OrderFilter struct {
+	Symbol      string // Название торговой пары
+	SettleCoin  string // Монета расчетов для запроса без Symbol (linear - USDT по умолчанию)
+	OrderId     string // ID ордера
+	OrderStatus string // Статус ордера (только история ордеров)
+	StartTime   int64  // Начало диапазона времени (мс)
+	EndTime     int64  // Конец диапазона времени (мс)
+	Limit       int    // Размер страницы
+	Cursor      string // Курсор страницы из предыдущего ответа
+}
+
+// query формирует параметры запроса, ограничивая размер страницы значением maxLimit
+func (f *OrderFilter) query(category string, maxLimit int) url.Values {
+	query := make(url.Values)
+	query.Set("category", category)
+	if f.Symbol != "" {
+		query.Set("symbol", f.Symbol)
+	} else if f.SettleCoin != "" {
+		query.Set("settleCoin", f.SettleCoin)
+	} else if category == "linear" {
+		query.Set("settleCoin", "USDT")
+	}
+	if f.OrderId != "" {
+		query.Set("orderId", f.OrderId)
+	}
+	if f.OrderStatus != "" {
+		query.Set("orderStatus", f.OrderStatus)
+	}
+	if f.StartTime > 0 {
+		query.Set("startTime", strconv.FormatInt(f.StartTime, 10))
+	}
+	if f.EndTime > 0 {
+		query.Set("endTime", strconv.FormatInt(f.EndTime, 10))
+	}
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(min(f.Limit, maxLimit)))
+	}
+	if f.Cursor != "" {
+		query.Set("cursor", f.Cursor)
+	}
+	return query
+}
+
+// getOrders запрашивает страницу ордеров по пути path
+func (c *Client) getOrders(endpoint, path string, filter OrderFilter) (*models.OrderHistoryResult, error) {
+	queryString := filter.query(c.category, ordersPageLimit).Encode()
+	req := httpx.Get(fmt.Sprintf("%s%s?%s", c.baseURL, path, queryString))
+	var orderHistoryResult models.OrderHistoryResult
+	if err := c.callAPI(req, queryString, &orderHistoryResult); err != nil {
+		return nil, err.(*Error).SetEndpoint(endpoint)
+	}
+
+	return &orderHistoryResult, nil
+}
+
+// GetOpenOrders возвращает страницу активных ордеров.
+// https://bybit-exchange.github.io/docs/v5/order/open-order
+func (c *Client) GetOpenOrders(filter OrderFilter) (*models.OrderHistoryResult, error) {
+	return c.getOrders("GetOpenOrders", "/v5/order/realtime", filter)
+}
+
+// GetOrderHistory возвращает страницу истории ордеров. Диапазон времени одного запроса - не более 7 дней.
+// https://bybit-exchange.github.io/docs/v5/order/order-list
+func (c *Client) GetOrderHistory(filter OrderFilter) (*models.OrderHistoryResult, error) {
+	return c.getOrders("GetOrderHistory", "/v5/order/history", filter)
+}
+
+// GetExecutions возвращает страницу истории исполнений. Диапазон времени одного запроса - не более 7 дней.
+// https://bybit-exchange.github.io/docs/v5/order/execution
+func (c *Client) GetExecutions(filter OrderFilter) (*models.ExecutionResult, error) {
+	queryString := filter.query(c.category, executionsPageLimit).Encode()
+	path := fmt.Sprintf("%s%s?%s", c.baseURL, "/v5/execution/list", queryString)
+	req := httpx.Get(path)
+	var executionResult models.ExecutionResult
+	if err := c.callAPI(req, queryString, &executionResult); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetExecutions")
+	}
+
+	return &executionResult, nil
+}
+
+// GetAllOpenOrders возвращает все активные ордера, проходя по страницам
+func (c *Client) GetAllOpenOrders(filter OrderFilter) ([]models.OrderHistoryDetail, error) {
+	filter.Limit = ordersPageLimit
+	return paginate(filter, func(f OrderFilter) ([]models.OrderHistoryDetail, string, error) {
+		res, err := c.GetOpenOrders(f)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.List, res.NextPageCursor, nil
+	})
+}
+
+// GetAllOrderHistory возвращает всю историю ордеров за диапазон времени фильтра,
+// разбивая его на интервалы по 7 дней (без StartTime - за последние 7 дней)
+func (c *Client) GetAllOrderHistory(filter OrderFilter) ([]models.OrderHistoryDetail, error) {
+	filter.Limit = ordersPageLimit
+	return paginateWindows(filter, func(f OrderFilter) ([]models.OrderHistoryDetail, string, error) {
+		res, err := c.GetOrderHistory(f)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.List, res.NextPageCursor, nil
+	})
+}
+
+// GetAllExecutions возвращает всю историю исполнений за диапазон времени фильтра,
+// разбивая его на интервалы по 7 дней (без StartTime - за последние 7 дней)
+func (c *Client) GetAllExecutions(filter OrderFilter) ([]models.Execution, error) {
+	filter.Limit = executionsPageLimit
+	return paginateWindows(filter, func(f OrderFilter) ([]models.Execution, string, error) {
+		res, err := c.GetExecutions(f)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.List, res.NextPageCursor, nil
+	})
+}
+
+// paginate запрашивает страницы, пока ответ содержит курсор следующей страницы
+func paginate[T any](filter OrderFilter, page func(OrderFilter) ([]T, string, error)) ([]T, error) {
+	var items []T
+	for {
+		list, cursor, err := page(filter)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, list...)
+		if cursor == "" || len(list) == 0 {
+			return items, nil
+		}
+		filter.Cursor = cursor
+	}
+}
+
+// paginateWindows выполняет paginate для каждого интервала диапазона времени фильтра
+func paginateWindows[T any](filter OrderFilter, page func(OrderFilter) ([]T, string, error)) ([]T, error) {
+	if filter.StartTime <= 0 {
+		return paginate(filter, page)
+	}
+	end := filter.EndTime
+	if end <= 0 {
+		end = time.Now().UnixMilli()
+	}
+
+	var items []T
+	window := historyWindow.Milliseconds()
+	for start := filter.StartTime; start <= end; start += window {
+		f := filter
+		f.StartTime = start
+		f.EndTime = min(start+window-1, end)
+		f.Cursor = ""
+		list, err := paginate(f, page)
+		items = append(items, list...)
+		if err != nil {
+			return items, err
+		}
+	}
+
+	return items, nil
+}
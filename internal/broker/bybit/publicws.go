@@ -0,0 +1,382 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+)
+
+const (
+	publicPingInterval = 20 * time.Second // Интервал {"op":"ping"}, рекомендованный биржей
+	publicTopicBuffer  = 256              // Буфер сообщений подписчика топика
+	publicArgsLimit    = 10               // Максимум топиков в одном сообщении подписки (spot)
+)
+
+var (
+	publicMuxes   = make(map[string]*publicMux)
+	publicMuxesMu sync.Mutex
+)
+
+// snapshotTopics - префиксы топиков, изменения которых накладываются на снимок
+var snapshotTopics = []string{"orderbook.", "tickers."}
+
+// topicSub - подписчик топика общего соединения
+type topicSub struct {
+	ch      chan []byte
+	done    chan struct{}
+	lagging atomic.Bool // Буфер подписчика был переполнен, сообщения пропускались
+}
+
+// publicMux - общее публичное WebSocket соединение категории с маршрутизацией
+// сообщений по топикам. Подписки восстанавливаются после переподключения.
+type publicMux struct {
+	url    string
+	conn   *ws.Conn
+	topics map[string]map[*topicSub]struct{}
+	sent   map[string]struct{} // Топики, подписка на которые отправлена в текущем соединении
+	closed bool
+	mu     sync.RWMutex
+	opMu   sync.Mutex // Упорядочивает изменение топиков и отправку подписок
+}
+
+// getPublicMux возвращает общее соединение для url, создавая его при первом обращении
+func getPublicMux(url string) (*publicMux, error) {
+	publicMuxesMu.Lock()
+	defer publicMuxesMu.Unlock()
+
+	if m, ok := publicMuxes[url]; ok {
+		return m, nil
+	}
+
+	m := &publicMux{
+		url:    url,
+		topics: make(map[string]map[*topicSub]struct{}),
+		sent:   make(map[string]struct{}),
+	}
+	ping, _ := json.Marshal(map[string]any{"op": "ping"})
+	conn, err := ws.Dial(
+		url,
+		context.Background(),
+		ws.WithHandshakeFunc(m.subscribeMessages),
+		ws.WithHeartbeat(ping, publicPingInterval),
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.conn = conn
+	publicMuxes[url] = m
+
+	states := make(chan ws.StateEvent, 8)
+	conn.SubscribeState(states)
+	go m.watchState(states)
+	go m.route()
+
+	return m, nil
+}
+
+// publicStream подписывается на топик общего публичного соединения категории клиента
+func (c *Client) publicStream(ctx context.Context, topic string) (*publicMux, <-chan []byte, error) {
+	m, err := getPublicMux(fmt.Sprintf("%s/%s", c.publicWS, c.category))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create websocket connection: %w", err)
+	}
+	return m, m.subscribe(ctx, topic), nil
+}
+
+// PublicStreamStats возвращает счетчики общего публичного соединения категории клиента
+func (c *Client) PublicStreamStats() (ws.Stats, error) {
+	m, err := getPublicMux(fmt.Sprintf("%s/%s", c.publicWS, c.category))
+	if err != nil {
+		return ws.Stats{}, NewError(InternalErrorT, err).SetEndpoint("PublicStreamStats")
+	}
+	return m.conn.Stats(), nil
+}
+
+// SubscribePublicState подписывает ch на события состояния общего публичного соединения категории
+func (c *Client) SubscribePublicState(ch chan<- ws.StateEvent) (chan<- struct{}, error) {
+	m, err := getPublicMux(fmt.Sprintf("%s/%s", c.publicWS, c.category))
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("SubscribePublicState")
+	}
+	return m.conn.SubscribeState(ch), nil
+}
+
+// StreamReconnects возвращает канал, в который отправляется значение после каждого
+// восстановления подписок общего публичного соединения. Канал закрывается при завершении ctx.
+func (c *Client) StreamReconnects(ctx context.Context) (<-chan struct{}, error) {
+	states := make(chan ws.StateEvent, 8)
+	done, err := c.SubscribePublicState(states)
+	if err != nil {
+		return nil, err
+	}
+
+	reconnects := make(chan struct{}, 1)
+	go func() {
+		defer close(reconnects)
+		for {
+			select {
+			case <-ctx.Done():
+				close(done)
+				return
+			case event, ok := <-states:
+				if !ok {
+					return
+				}
+				if event.State != ws.Resubscribed {
+					continue
+				}
+				select {
+				case reconnects <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return reconnects, nil
+}
+
+// opMessage формирует сообщение операции над топиками
+func opMessage(op string, topics []string) []byte {
+	msg, _ := json.Marshal(map[string]any{
+		"req_id": uuid.NewString(),
+		"op":     op,
+		"args":   topics,
+	})
+	return msg
+}
+
+// subscribeMessages формирует сообщения подписки на все активные топики
+func (m *publicMux) subscribeMessages() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	topics := make([]string, 0, len(m.topics))
+	clear(m.sent)
+	for topic := range m.topics {
+		topics = append(topics, topic)
+		m.sent[topic] = struct{}{}
+	}
+	return opMessages("subscribe", topics)
+}
+
+// opMessages разбивает операцию над топиками на сообщения по publicArgsLimit топиков
+func opMessages(op string, topics []string) [][]byte {
+	var messages [][]byte
+	for i := 0; i < len(topics); i += publicArgsLimit {
+		messages = append(messages, opMessage(op, topics[i:min(i+publicArgsLimit, len(topics))]))
+	}
+	return messages
+}
+
+// isSnapshotTopic сообщает, что изменения топика накладываются на снимок
+func isSnapshotTopic(topic string) bool {
+	for _, prefix := range snapshotTopics {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe добавляет подписчика топика. Подписка отменяется при завершении ctx.
+// Новый подписчик топика со снимками получает снимок через повторную подписку.
+func (m *publicMux) subscribe(ctx context.Context, topic string) <-chan []byte {
+	sub := &topicSub{
+		ch:   make(chan []byte, publicTopicBuffer),
+		done: make(chan struct{}),
+	}
+
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		close(sub.ch)
+		return sub.ch
+	}
+	subs, ok := m.topics[topic]
+	if !ok {
+		subs = make(map[*topicSub]struct{})
+		m.topics[topic] = subs
+	}
+	subs[sub] = struct{}{}
+	m.mu.Unlock()
+
+	switch {
+	case !ok:
+		m.sendOp("subscribe", topic)
+	case isSnapshotTopic(topic):
+		m.sendOp("unsubscribe", topic)
+		m.sendOp("subscribe", topic)
+	}
+
+	go func() {
+		<-ctx.Done()
+		m.unsubscribe(topic, sub)
+	}()
+
+	return sub.ch
+}
+
+// unsubscribe удаляет подписчика и отписывается от топика, если подписчиков не осталось
+func (m *publicMux) unsubscribe(topic string, sub *topicSub) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.Lock()
+	close(sub.done)
+	subs := m.topics[topic]
+	delete(subs, sub)
+	empty := len(subs) == 0
+	if empty {
+		delete(m.topics, topic)
+	}
+	m.mu.Unlock()
+
+	if empty {
+		m.sendOp("unsubscribe", topic)
+	}
+}
+
+// resubscribe повторно подписывается на топик, чтобы получить новый снимок данных
+func (m *publicMux) resubscribe(topic string) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.RLock()
+	_, ok := m.topics[topic]
+	m.mu.RUnlock()
+	if ok {
+		m.sendOp("unsubscribe", topic)
+		m.sendOp("subscribe", topic)
+	}
+}
+
+// sendOp отправляет операцию над топиком и отмечает, подписано ли соединение на топик.
+// Вызывается под m.opMu. Не отправленная при разрыве подписка будет отправлена
+// в рукопожатии или при сверке после подключения.
+func (m *publicMux) sendOp(op, topic string) {
+	err := m.conn.Send(opMessage(op, []string{topic}))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case op == "unsubscribe":
+		delete(m.sent, topic)
+	case err == nil:
+		m.sent[topic] = struct{}{}
+	}
+}
+
+// watchState сверяет подписки после каждого подключения: топики, добавленные
+// после формирования рукопожатия, могли не попасть ни в него, ни в соединение
+func (m *publicMux) watchState(states <-chan ws.StateEvent) {
+	for event := range states {
+		if event.State == ws.Connected {
+			m.reconcile()
+		}
+	}
+}
+
+// reconcile подписывается на активные топики, не отправленные в текущем соединении,
+// и отписывается от топиков, подписчиков которых не осталось
+func (m *publicMux) reconcile() {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	var subscribe, unsubscribe []string
+	m.mu.RLock()
+	for topic := range m.topics {
+		if _, ok := m.sent[topic]; !ok {
+			subscribe = append(subscribe, topic)
+		}
+	}
+	for topic := range m.sent {
+		if _, ok := m.topics[topic]; !ok {
+			unsubscribe = append(unsubscribe, topic)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, topic := range unsubscribe {
+		m.sendOp("unsubscribe", topic)
+	}
+	for _, topic := range subscribe {
+		m.sendOp("subscribe", topic)
+	}
+}
+
+// route распределяет входящие сообщения по подписчикам топиков.
+// После окончательного завершения соединения каналы подписчиков закрываются.
+func (m *publicMux) route() {
+	defer m.close()
+
+	for data := range m.conn.Messages() {
+		var msg struct {
+			Topic string `json:"topic"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Topic == "" {
+			continue
+		}
+
+		m.mu.RLock()
+		subs := make([]*topicSub, 0, len(m.topics[msg.Topic]))
+		for sub := range m.topics[msg.Topic] {
+			subs = append(subs, sub)
+		}
+		m.mu.RUnlock()
+
+		for _, sub := range subs {
+			m.deliver(msg.Topic, sub, data)
+		}
+	}
+}
+
+// deliver отправляет сообщение подписчику без ожидания. Если подписчик не успевает читать,
+// сообщение пропускается, чтобы не задерживать остальные топики и чтение соединения.
+// Когда подписчик снова принимает сообщения, топики со снимками переподписываются.
+func (m *publicMux) deliver(topic string, sub *topicSub, data []byte) {
+	select {
+	case <-sub.done:
+		return
+	default:
+	}
+
+	select {
+	case sub.ch <- data:
+		if sub.lagging.Swap(false) && isSnapshotTopic(topic) {
+			// Отправка может ожидать до таймаута записи, поэтому выполняется вне маршрутизации
+			go m.resubscribe(topic)
+		}
+	default:
+		sub.lagging.Store(true)
+	}
+}
+
+// close удаляет завершенное соединение из списка общих и закрывает каналы подписчиков
+func (m *publicMux) close() {
+	publicMuxesMu.Lock()
+	if publicMuxes[m.url] == m {
+		delete(publicMuxes, m.url)
+	}
+	publicMuxesMu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	for _, subs := range m.topics {
+		for sub := range subs {
+			close(sub.ch)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package bybit
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/url"
@@ -11,9 +12,33 @@ import (
 	"github.com/nikita55612/httpx"
 )
 
-// PlaceOrder создает рыночный или лимитный ордер.
+// PlaceOrder создает рыночный или лимитный ордер. При включенном WebSocket API
+// ордер отправляется через него, если соединение недоступно - через REST.
 // https://bybit-exchange.github.io/docs/v5/order/create-order
+// https://bybit-exchange.github.io/docs/v5/websocket/trade/guideline
 func (c *Client) PlaceOrder(symbol string, qty float64, price *float64) (string, error) {
+	params := c.placeOrderParams(symbol, qty, price)
+	var placeOrderResult models.PlaceOrderResult
+	if err := c.tradeWSRequest("order.create", params, &placeOrderResult); err != nil {
+		if !errors.Is(err, errTradeWSUnavailable) {
+			return "", err.(*Error).SetEndpoint("PlaceOrder")
+		}
+		jsonData, _ := json.Marshal(params)
+		path := fmt.Sprintf("%s%s", c.baseURL, "/v5/order/create")
+		req := httpx.Post(path).WithData(jsonData)
+		if err := c.callAPI(req, string(jsonData), &placeOrderResult); err != nil {
+			return "", err.(*Error).SetEndpoint("PlaceOrder")
+		}
+	}
+	if c.tradeWS != nil {
+		c.tradeWS.rememberOrder(placeOrderResult.OrderId, symbol)
+	}
+
+	return placeOrderResult.OrderId, nil
+}
+
+// placeOrderParams формирует параметры создания рыночного или лимитного ордера
+func (c *Client) placeOrderParams(symbol string, qty float64, price *float64) map[string]any {
 	params := map[string]any{
 		"category":   c.category,
 		"symbol":     symbol,
@@ -24,35 +49,94 @@ func (c *Client) PlaceOrder(symbol string, qty float64, price *float64) (string,
 	if qty < 0 {
 		params["side"] = "Sell"
 	}
+	if c.category == "spot" {
+		// Без маржи, количество рыночного ордера всегда в базовой монете
+		params["isLeverage"] = 0
+		params["marketUnit"] = "baseCoin"
+	}
 	params["qty"] = strconv.FormatFloat(math.Abs(qty), 'f', -1, 64)
 	if price != nil {
 		params["price"] = strconv.FormatFloat(*price, 'f', -1, 64)
 		params["orderType"] = "Limit"
 	}
+
+	return params
+}
+
+// AmendOrder изменяет количество и/или цену активного ордера (nil - без изменений).
+// При включенном WebSocket API запрос отправляется через него, если соединение недоступно - через REST.
+// https://bybit-exchange.github.io/docs/v5/order/amend-order
+func (c *Client) AmendOrder(symbol, orderId string, qty *float64, price *float64) (string, error) {
+	params := c.amendOrderParams(symbol, orderId, qty, price)
+	var amendOrderResult models.AmendOrderResult
+	if err := c.tradeWSRequest("order.amend", params, &amendOrderResult); err == nil {
+		return amendOrderResult.OrderId, nil
+	} else if !errors.Is(err, errTradeWSUnavailable) {
+		return "", err.(*Error).SetEndpoint("AmendOrder")
+	}
+
 	jsonData, _ := json.Marshal(params)
-	path := fmt.Sprintf("%s%s", c.baseURL, "/v5/order/create")
+	path := fmt.Sprintf("%s%s", c.baseURL, "/v5/order/amend")
 	req := httpx.Post(path).WithData(jsonData)
-	var placeOrderResult models.PlaceOrderResult
-	if err := c.callAPI(req, string(jsonData), &placeOrderResult); err != nil {
-		return "", err.(*Error).SetEndpoint("PlaceOrder")
+	if err := c.callAPI(req, string(jsonData), &amendOrderResult); err != nil {
+		return "", err.(*Error).SetEndpoint("AmendOrder")
 	}
 
-	return placeOrderResult.OrderId, nil
+	return amendOrderResult.OrderId, nil
 }
 
-// CancelOrder отменяет активный ордер.
+// amendOrderParams формирует параметры изменения ордера
+func (c *Client) amendOrderParams(symbol, orderId string, qty *float64, price *float64) map[string]any {
+	params := map[string]any{
+		"category": c.category,
+		"symbol":   symbol,
+		"orderId":  orderId,
+	}
+	if qty != nil {
+		params["qty"] = strconv.FormatFloat(math.Abs(*qty), 'f', -1, 64)
+	}
+	if price != nil {
+		params["price"] = strconv.FormatFloat(*price, 'f', -1, 64)
+	}
+
+	return params
+}
+
+// CancelOrder отменяет активный ордер. При включенном WebSocket API запрос отправляется
+// через него, если ордер создан этим клиентом и соединение доступно, иначе - через REST.
 // https://bybit-exchange.github.io/docs/v5/order/cancel-order
 func (c *Client) CancelOrder(orderId string) (string, error) {
+	var cancelOrderResult models.CancelOrderResult
+	if c.tradeWS != nil {
+		if symbol, ok := c.tradeWS.orderSymbol(orderId); ok {
+			params := map[string]any{
+				"category": c.category,
+				"symbol":   symbol,
+				"orderId":  orderId,
+			}
+			err := c.tradeWSRequest("order.cancel", params, &cancelOrderResult)
+			if err == nil {
+				c.tradeWS.forgetOrder(orderId)
+				return cancelOrderResult.OrderId, nil
+			}
+			if !errors.Is(err, errTradeWSUnavailable) {
+				return "", err.(*Error).SetEndpoint("CancelOrder")
+			}
+		}
+	}
+
 	query := make(url.Values)
 	query.Set("category", c.category)
 	query.Set("orderId", orderId)
 	queryString := query.Encode()
 	path := fmt.Sprintf("%s%s?%s", c.baseURL, "/v5/order/cancel", queryString)
 	req := httpx.Post(path)
-	var cancelOrderResult models.CancelOrderResult
 	if err := c.callAPI(req, queryString, &cancelOrderResult); err != nil {
 		return "", err.(*Error).SetEndpoint("CancelOrder")
 	}
+	if c.tradeWS != nil {
+		c.tradeWS.forgetOrder(orderId)
+	}
 
 	return cancelOrderResult.OrderId, nil
 }
@@ -74,6 +158,10 @@ func (c *Client) GetOrderHistoryDetail(orderId string) (*models.OrderHistoryDeta
 		err := fmt.Errorf("order with id %s not found", orderId)
 		return nil, NewError(InternalErrorT, err).SetEndpoint("GetOrderHistoryDetail")
 	}
+	detail := &orderHistoryResult.List[0]
+	if c.tradeWS != nil && isOrderClosed(detail.OrderStatus) {
+		c.tradeWS.forgetOrder(orderId)
+	}
 
-	return &orderHistoryResult.List[0], nil
+	return detail, nil
 }
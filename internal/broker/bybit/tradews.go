@@ -0,0 +1,226 @@
+package bybit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+)
+
+const (
+	TRADEWS        = "wss://stream.bybit.com/v5/trade"
+	TRADEWSTESTNET = "wss://stream-testnet.bybit.com/v5/trade"
+)
+
+const (
+	tradeWSPingInterval  = 20 * time.Second // Интервал {"op":"ping"}, рекомендованный биржей
+	tradeWSRedialBackoff = 30 * time.Second // Пауза между попытками открыть соединение после неудачи
+	tradeWSAuthExpires   = 10 * time.Second // Срок действия подписи аутентификации
+)
+
+// errTradeWSUnavailable - запрос не был отправлен через WebSocket, можно использовать REST
+var errTradeWSUnavailable = errors.New("trade websocket is unavailable")
+
+// WithTradeWS включает отправку ордеров через WebSocket API по адресу url (TRADEWS или TRADEWSTESTNET).
+// Пока соединение не установлено или не аутентифицировано, используется REST API.
+func WithTradeWS(url string) Option {
+	return func(c *Client) {
+		c.tradeWS = &tradeWS{url: url, symbols: make(map[string]string)}
+	}
+}
+
+// tradeWS - аутентифицированное соединение WebSocket API для работы с ордерами
+type tradeWS struct {
+	url      string
+	conn     *ws.Conn
+	ready    atomic.Bool // Соединение установлено и аутентифицировано
+	dialing  bool        // Соединение открывается в фоне
+	lastDial time.Time
+	symbols  map[string]string // Символы активных ордеров для запросов, требующих symbol
+	mu       sync.Mutex
+}
+
+// rememberOrder сохраняет символ созданного ордера до его закрытия
+func (t *tradeWS) rememberOrder(orderId, symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.symbols[orderId] = symbol
+}
+
+// orderSymbol возвращает символ ордера, созданного клиентом
+func (t *tradeWS) orderSymbol(orderId string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	symbol, ok := t.symbols[orderId]
+	return symbol, ok
+}
+
+// forgetOrder удаляет символ закрытого ордера
+func (t *tradeWS) forgetOrder(orderId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.symbols, orderId)
+}
+
+// authMessage формирует сообщение аутентификации с подписью "GET/realtime{expires}"
+func (c *Client) authMessage() [][]byte {
+	expires := time.Now().Add(tradeWSAuthExpires).UnixMilli()
+	mac := hmac.New(sha256.New, []byte(c.apiSecret))
+	mac.Write(fmt.Appendf(nil, "GET/realtime%d", expires))
+	msg, _ := json.Marshal(map[string]any{
+		"op":   "auth",
+		"args": []any{c.apiKey, expires, hex.EncodeToString(mac.Sum(nil))},
+	})
+	return [][]byte{msg}
+}
+
+// tradeConn возвращает готовое к отправке ордеров соединение. Если соединения нет,
+// оно открывается в фоне, а запрос выполняется через REST.
+func (c *Client) tradeConn() (*ws.Conn, error) {
+	t := c.tradeWS
+	if t == nil {
+		return nil, errTradeWSUnavailable
+	}
+
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil && !t.dialing && time.Since(t.lastDial) >= tradeWSRedialBackoff {
+		t.dialing = true
+		t.lastDial = time.Now()
+		go c.dialTradeWS()
+	}
+	t.mu.Unlock()
+
+	if conn == nil || !t.ready.Load() {
+		return nil, errTradeWSUnavailable
+	}
+
+	return conn, nil
+}
+
+// dialTradeWS открывает и аутентифицирует соединение без удержания t.mu,
+// чтобы запросы через REST не ожидали подключения
+func (c *Client) dialTradeWS() {
+	t := c.tradeWS
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ping, _ := json.Marshal(map[string]any{"op": "ping"})
+	conn, err := ws.Dial(
+		t.url,
+		ctx,
+		ws.WithHandshakeFunc(c.authMessage),
+		ws.WithHeartbeat(ping, tradeWSPingInterval),
+	)
+
+	t.mu.Lock()
+	t.dialing = false
+	if err == nil {
+		t.conn = conn
+	}
+	t.mu.Unlock()
+
+	if err == nil {
+		go t.watch(conn)
+	}
+}
+
+// watch отслеживает аутентификацию и разрывы соединения
+func (t *tradeWS) watch(conn *ws.Conn) {
+	states := make(chan ws.StateEvent, 8)
+	conn.SubscribeState(states)
+
+	messages := conn.Messages()
+	for {
+		select {
+		case event, ok := <-states:
+			if !ok {
+				states = nil
+				continue
+			}
+			if event.State == ws.Disconnected {
+				t.ready.Store(false)
+			}
+		case data, ok := <-messages:
+			if !ok {
+				// Соединение завершено окончательно, следующий запрос откроет новое
+				t.ready.Store(false)
+				t.mu.Lock()
+				if t.conn == conn {
+					t.conn = nil
+				}
+				t.mu.Unlock()
+				return
+			}
+			var resp models.TradeStreamResponse
+			if err := json.Unmarshal(data, &resp); err != nil {
+				continue
+			}
+			if resp.Op == "auth" {
+				t.ready.Store(resp.RetCode == 0)
+			}
+		}
+	}
+}
+
+// tradeWSRequest выполняет операцию op над ордером через WebSocket API.
+// Возвращает errTradeWSUnavailable, если запрос не был отправлен.
+func (c *Client) tradeWSRequest(op string, params map[string]any, result any) error {
+	conn, err := c.tradeConn()
+	if err != nil {
+		return err
+	}
+
+	reqID := uuid.NewString()
+	msg, _ := json.Marshal(map[string]any{
+		"reqId": reqID,
+		"header": map[string]string{
+			"X-BAPI-TIMESTAMP":   strconv.FormatInt(time.Now().UnixMilli(), 10),
+			"X-BAPI-RECV-WINDOW": strconv.Itoa(c.recvWindow),
+		},
+		"op":   op,
+		"args": []any{params},
+	})
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	data, err := conn.Request(ctx, reqID, msg)
+	if errors.Is(err, ws.ErrNotConnected) {
+		return errTradeWSUnavailable
+	}
+	if err != nil {
+		// Ордер мог быть отправлен, повтор через REST небезопасен
+		return NewError(RequestErrorT, err)
+	}
+
+	var resp models.TradeStreamResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return NewError(SerDeErrorT, err)
+	}
+	if resp.RetCode != 0 {
+		return NewError(ServerResponseErrorT, &serverResponseError{msg: resp.RetMsg, code: resp.RetCode})
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Data, result); err != nil {
+			return NewError(SerDeErrorT, err)
+		}
+	}
+
+	return nil
+}
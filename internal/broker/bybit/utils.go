@@ -5,7 +5,10 @@ import (
 	"strconv"
 
 	"github.com/nikita55612/goTradingBot/internal/broker/bybit/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/book"
 	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/tick"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ticker"
 )
 
 // AsLocalInterval преобразует cdl.Interval в локальный формат интервала.
@@ -73,6 +76,102 @@ func candleStreamFromRawData(d *models.CandleStreamRawData) (*cdl.CandleStreamDa
 	}, nil
 }
 
+// orderBookUpdateFromRawData преобразует сырые данные книги ордеров из WebSocket в обновление.
+func orderBookUpdateFromRawData(d *models.OrderBookStreamRawData) (*book.Update, error) {
+	bids, err := levelsFromRawData(d.Data.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := levelsFromRawData(d.Data.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Update{
+		Symbol:   d.Data.Symbol,
+		Snapshot: d.Type == "snapshot",
+		Bids:     bids,
+		Asks:     asks,
+		UpdateID: d.Data.UpdateID,
+		Seq:      d.Data.Seq,
+		Time:     d.Ts,
+	}, nil
+}
+
+// levelsFromRawData преобразует уровни [цена, размер] в структурированный формат.
+func levelsFromRawData(raw [][2]string) ([]book.Level, error) {
+	levels := make([]book.Level, len(raw))
+	for i, v := range raw {
+		price, err := strconv.ParseFloat(v[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseFloat(v[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		levels[i] = book.Level{Price: price, Size: size}
+	}
+	return levels, nil
+}
+
+// ticksFromRawData преобразует сырые данные публичных сделок из WebSocket в сделки.
+func ticksFromRawData(d *models.PublicTradeStreamRawData) ([]*tick.Tick, error) {
+	ticks := make([]*tick.Tick, len(d.Data))
+	for i, v := range d.Data {
+		price, err := strconv.ParseFloat(v.Price, 64)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseFloat(v.Size, 64)
+		if err != nil {
+			return nil, err
+		}
+		side := tick.Buy
+		if v.Side == "Sell" {
+			side = tick.Sell
+		}
+		ticks[i] = &tick.Tick{
+			ID:         v.ID,
+			Symbol:     v.Symbol,
+			Time:       v.Time,
+			Side:       side,
+			Price:      price,
+			Size:       size,
+			BlockTrade: v.BlockTrade,
+		}
+	}
+	return ticks, nil
+}
+
+// tickerFromRawData преобразует тикер биржи в структурированный формат.
+// Поля, отсутствующие в категории инструментов, остаются нулевыми.
+func tickerFromRawData(t *models.Ticker) *ticker.Ticker {
+	parse := func(s string) float64 {
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+	nextFundingTime, _ := strconv.ParseInt(t.NextFundingTime, 10, 64)
+
+	return &ticker.Ticker{
+		Symbol:            t.Symbol,
+		LastPrice:         parse(t.LastPrice),
+		MarkPrice:         parse(t.MarkPrice),
+		IndexPrice:        parse(t.IndexPrice),
+		BidPrice:          parse(t.Bid1Price),
+		BidSize:           parse(t.Bid1Size),
+		AskPrice:          parse(t.Ask1Price),
+		AskSize:           parse(t.Ask1Size),
+		OpenInterest:      parse(t.OpenInterest),
+		OpenInterestValue: parse(t.OpenInterestValue),
+		FundingRate:       parse(t.FundingRate),
+		NextFundingTime:   nextFundingTime,
+		Price24hPcnt:      parse(t.Price24hPcnt),
+		Volume24h:         parse(t.Volume24h),
+		Turnover24h:       parse(t.Turnover24h),
+	}
+}
+
 // extractCandleFromRawData преобразует массив сырых свечей в массив структурированных свечей
 func extractCandleFromResult(res *models.CandleResult) ([]cdl.Candle, error) {
 	candles := make([]cdl.Candle, len(res.List))
@@ -87,3 +186,20 @@ func extractCandleFromResult(res *models.CandleResult) ([]cdl.Candle, error) {
 
 	return candles, nil
 }
+
+// parseFloatOrZero разбирает число, пустая строка соответствует нулю
+func parseFloatOrZero(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// isOrderClosed сообщает, что ордер со статусом status больше не может исполняться
+func isOrderClosed(status string) bool {
+	switch status {
+	case "New", "PartiallyFilled", "Untriggered":
+		return false
+	}
+	return true
+}
@@ -13,4 +13,31 @@ type Broker interface {
 	PlaceOrder(symbol string, qty float64, price *float64) (string, error)
 	CancelOrder(orderId string) (string, error)
 	GetOrder(orderId string) ([]byte, error)
+	GetOpenOrders(symbol string) ([]byte, error)
+	PlaceOrders(orders []OrderParams) ([]BatchResult, error)
+	AmendOrders(orders []AmendParams) ([]BatchResult, error)
+	CancelOrders(symbol string, orderIds []string) ([]BatchResult, error)
+	CancelAllOrders(symbol string) ([]string, error)
+}
+
+// OrderParams - параметры создания ордера в пакетном запросе
+type OrderParams struct {
+	Symbol string   // Название торговой пары
+	Qty    float64  // Количество, отрицательное для продажи
+	Price  *float64 // Цена лимитного ордера (nil - рыночный ордер)
+}
+
+// AmendParams - параметры изменения ордера в пакетном запросе
+type AmendParams struct {
+	Symbol  string   // Название торговой пары
+	OrderId string   // ID ордера
+	Qty     *float64 // Новое количество (nil - без изменений)
+	Price   *float64 // Новая цена (nil - без изменений)
+}
+
+// BatchResult - результат операции над ордером пакетного запроса
+// в порядке ордеров запроса
+type BatchResult struct {
+	OrderId string // ID ордера
+	Err     error  // Ошибка операции над ордером
 }
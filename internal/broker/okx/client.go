@@ -0,0 +1,238 @@
+package okx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/nikita55612/goTradingBot/internal/broker/okx/models"
+	"github.com/nikita55612/httpx"
+)
+
+const (
+	MAINNET        = "https://www.okx.com"
+	BUSINESSWS     = "wss://ws.okx.com:8443/ws/v5/business"
+	BUSINESSWSDEMO = "wss://wspap.okx.com:8443/ws/v5/business"
+)
+
+// ServerResponse представляет общий ответ API OKX
+type ServerResponse struct {
+	Code string          `json:"code"` // Code - код ответа ("0" - успех)
+	Msg  string          `json:"msg"`  // Msg - описание ошибки
+	Data json.RawMessage `json:"data"` // Data - данные ответа
+}
+
+// Client представляет клиент для работы с API v5 OKX
+type Client struct {
+	baseURL     string                        // базовый URL API
+	wsURL       string                        // URL бизнес-потоков WebSocket (свечи)
+	apiKey      string                        // публичный API-ключ для аутентификации
+	apiSecret   string                        // секретный ключ для подписи запросов (HMAC)
+	passphrase  string                        // парольная фраза API-ключа
+	instType    string                        // тип инструментов: SPOT, SWAP
+	demo        bool                          // демо-торговля (заголовок x-simulated-trading)
+	ctx         context.Context               // контекст для выполнения запросов
+	timeout     time.Duration                 // таймаут HTTP-запросов
+	instruments map[string]*models.Instrument // кэш инструментов для пересчета контрактов
+	instMu      sync.Mutex
+}
+
+// NewClient создает новый экземпляр клиента для работы с API OKX
+// Принимает опциональные параметры конфигурации через Option функции
+func NewClient(apiKey, apiSecret, passphrase string, opts ...Option) *Client {
+	client := &Client{
+		baseURL:     MAINNET,
+		wsURL:       BUSINESSWS,
+		apiKey:      apiKey,
+		apiSecret:   apiSecret,
+		passphrase:  passphrase,
+		instType:    "SWAP",
+		timeout:     5 * time.Second,
+		instruments: make(map[string]*models.Instrument),
+	}
+	for _, option := range opts {
+		option(client)
+	}
+	return client
+}
+
+func NewClientFromEnv(opts ...Option) *Client {
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("%s: NewClientFromEnv: error loading .env file", errorTitel)
+	}
+	apiKey := os.Getenv("OKX_API_KEY")
+	if apiKey == "" {
+		log.Fatalf("%s: NewClientFromEnv: OKX_API_KEY not specified", errorTitel)
+	}
+	apiSecret := os.Getenv("OKX_API_SECRET")
+	if apiSecret == "" {
+		log.Fatalf("%s: NewClientFromEnv: OKX_API_SECRET not specified", errorTitel)
+	}
+	passphrase := os.Getenv("OKX_API_PASSPHRASE")
+	if passphrase == "" {
+		log.Fatalf("%s: NewClientFromEnv: OKX_API_PASSPHRASE not specified", errorTitel)
+	}
+	return NewClient(apiKey, apiSecret, passphrase, opts...)
+}
+
+// Option определяет тип функции для настройки Client
+type Option func(*Client)
+
+// WithContext устанавливает контекст для выполнения запросов
+func WithContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithTimeout устанавливает таймаут для HTTP-запросов
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithCategory устанавливает категорию инструментов в терминах брокера:
+// spot - спот (SPOT), linear - бессрочные контракты (SWAP)
+func WithCategory(category string) Option {
+	return func(c *Client) {
+		switch category {
+		case "spot":
+			c.instType = "SPOT"
+		default:
+			c.instType = "SWAP"
+		}
+	}
+}
+
+// WithDemo включает демо-торговлю: запросы помечаются заголовком x-simulated-trading,
+// потоки подключаются к демо-серверу
+func WithDemo() Option {
+	return func(c *Client) {
+		c.demo = true
+		c.wsURL = BUSINESSWSDEMO
+	}
+}
+
+// WithBaseURL устанавливает пользовательский базовый URL API
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithWSURL устанавливает пользовательский URL бизнес-потоков WebSocket
+func WithWSURL(url string) Option {
+	return func(c *Client) {
+		c.wsURL = url
+	}
+}
+
+// category возвращает категорию инструментов клиента в терминах брокера
+func (c *Client) category() string {
+	if c.instType == "SPOT" {
+		return "spot"
+	}
+	return "linear"
+}
+
+// sign возвращает подпись запроса: Base64(HMAC SHA256(timestamp + method + requestPath + body))
+func (c *Client) sign(timestamp, method, requestPath, body string) string {
+	mac := hmac.New(sha256.New, []byte(c.apiSecret))
+	mac.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// do выполняет запрос method к path и возвращает разобранный ответ без проверки кода.
+// Параметры передаются в строке запроса, body - в теле запроса в формате JSON.
+func (c *Client) do(method, path string, query url.Values, body any, signed bool) (*ServerResponse, error) {
+	requestPath := path
+	if len(query) > 0 {
+		requestPath = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+	var bodyData []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, NewError(SerDeErrorT, err)
+		}
+		bodyData = data
+	}
+
+	req := httpx.NewRequestBuilder(method, c.baseURL+requestPath).WithHeader(
+		"Content-Type", "application/json",
+		"Accept", "application/json",
+	)
+	if bodyData != nil {
+		req = req.WithData(bodyData)
+	}
+	if signed {
+		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		req = req.WithHeader(
+			"OK-ACCESS-KEY", c.apiKey,
+			"OK-ACCESS-SIGN", c.sign(timestamp, method, requestPath, string(bodyData)),
+			"OK-ACCESS-TIMESTAMP", timestamp,
+			"OK-ACCESS-PASSPHRASE", c.passphrase,
+		)
+	}
+	if c.demo {
+		req = req.WithHeader("x-simulated-trading", "1")
+	}
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	if c.timeout > 0 {
+		req = req.WithTimeout(c.timeout)
+	}
+	res, err := req.Build().Do()
+	if err != nil {
+		return nil, NewError(RequestErrorT, err)
+	}
+	defer res.Close()
+
+	data, err := res.ReadBody()
+	if err != nil {
+		return nil, NewError(RequestErrorT, err)
+	}
+	var serverResponse ServerResponse
+	if err := json.Unmarshal(data, &serverResponse); err != nil || serverResponse.Code == "" {
+		if res.StatusCode != http.StatusOK {
+			err := fmt.Errorf("unexpected response status: %s", res.Status)
+			return nil, NewError(RequestErrorT, err)
+		}
+		if err == nil {
+			err = fmt.Errorf("response code is missing")
+		}
+		return nil, NewError(SerDeErrorT, err)
+	}
+
+	return &serverResponse, nil
+}
+
+// callAPI выполняет запрос и разбирает данные успешного ответа в result
+func (c *Client) callAPI(method, path string, query url.Values, body any, signed bool, result any) error {
+	serverResponse, err := c.do(method, path, query, body, signed)
+	if err != nil {
+		return err
+	}
+	if serverResponse.Code != "0" {
+		return ErrorFromServerResponse(serverResponse)
+	}
+	if result != nil {
+		if err := json.Unmarshal(serverResponse.Data, result); err != nil {
+			return NewError(SerDeErrorT, err)
+		}
+	}
+
+	return nil
+}
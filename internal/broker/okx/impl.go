@@ -0,0 +1,202 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/okx/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+func (c *Client) BrokerImpl() broker.Broker {
+	return &BrokerImpl{cli: c}
+}
+
+type BrokerImpl struct {
+	cli *Client
+}
+
+// GetInstrumentInfo возвращает информацию об инструменте в формате брокера. Количество
+// указывается в базовой монете, минимальная сумма ордера рассчитывается по последней цене.
+func (b *BrokerImpl) GetInstrumentInfo(symbol string) ([]byte, error) {
+	info, err := b.cli.GetInstrumentInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+	ctVal, err := b.cli.contractValue(symbol)
+	if err != nil {
+		return nil, err
+	}
+	ticker, err := b.cli.GetTicker(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	lotSz, parseErr := strconv.ParseFloat(info.LotSz, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	minSz, parseErr := strconv.ParseFloat(info.MinSz, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	tickSize, parseErr := strconv.ParseFloat(info.TickSz, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	lastPrice, parseErr := strconv.ParseFloat(ticker.Last, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	infoData := map[string]any{
+		"qtyPrecision": numeric.DecimalPlaces(numeric.RoundFloat(lotSz*ctVal, 8)),
+		"minOrderAmt":  minSz * ctVal * lastPrice,
+		"tickSize":     tickSize,
+		"category":     b.cli.category(),
+	}
+
+	return json.Marshal(infoData)
+}
+
+func (b *BrokerImpl) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	return b.cli.GetCandles(symbol, interval, limit)
+}
+
+func (b *BrokerImpl) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	return b.cli.CandleStream(ctx, symbol, interval)
+}
+
+func (b *BrokerImpl) PlaceOrder(symbol string, qty float64, price *float64) (string, error) {
+	return b.cli.PlaceOrder(symbol, qty, price)
+}
+
+func (b *BrokerImpl) CancelOrder(orderId string) (string, error) {
+	return b.cli.CancelOrder(orderId)
+}
+
+func (b *BrokerImpl) PlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchPlaceOrders(orders)
+}
+
+func (b *BrokerImpl) AmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	return b.cli.BatchAmendOrders(orders)
+}
+
+func (b *BrokerImpl) CancelOrders(symbol string, orderIds []string) ([]broker.BatchResult, error) {
+	return b.cli.BatchCancelOrders(symbol, orderIds)
+}
+
+func (b *BrokerImpl) CancelAllOrders(symbol string) ([]string, error) {
+	return b.cli.CancelAllOrders(symbol)
+}
+
+func (b *BrokerImpl) GetOrder(orderId string) ([]byte, error) {
+	order, err := b.cli.GetOrder(orderId)
+	if err != nil {
+		return nil, err
+	}
+	orderData, err := b.orderData(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(orderData)
+}
+
+// GetOpenOrders возвращает активные ордера инструмента (пустой symbol - все инструменты типа)
+// в формате GetOrder
+func (b *BrokerImpl) GetOpenOrders(symbol string) ([]byte, error) {
+	openOrders, err := b.cli.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]map[string]any, 0, len(openOrders))
+	for i := range openOrders {
+		orderData, err := b.orderData(&openOrders[i])
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, orderData)
+	}
+
+	return json.Marshal(orders)
+}
+
+// orderData преобразует ордер в общий формат брокера. Количество контрактов
+// пересчитывается в базовую монету.
+func (b *BrokerImpl) orderData(order *models.Order) (map[string]any, error) {
+	ctVal, err := b.cli.contractValue(order.InstId)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, parseErr := strconv.ParseInt(order.CTime, 10, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	updatedAt, parseErr := strconv.ParseInt(order.UTime, 10, 64)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	qty, parseErr := parseFloatOrZero(order.Sz)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	price, parseErr := parseFloatOrZero(order.Px)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	avgPrice, parseErr := parseFloatOrZero(order.AvgPx)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	execQty, parseErr := parseFloatOrZero(order.AccFillSz)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	qty *= ctVal
+	execQty *= ctVal
+	execValue := execQty * avgPrice
+	if order.Side == "sell" {
+		qty = -qty
+		execQty = -execQty
+		execValue = -execValue
+	}
+	fee, parseErr := parseFloatOrZero(order.Fee)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	// Списанная комиссия отрицательна
+	fee = -fee
+	if b.cli.instType == "SPOT" && order.Side == "buy" {
+		// Комиссия спотовой покупки списывается в базовой монете
+		fee *= avgPrice
+	}
+	isClosed := true
+	switch order.State {
+	case "live", "partially_filled":
+		isClosed = false
+	}
+	orderData := map[string]any{
+		"id":        formatOrderId(order.InstId, order.OrdId),
+		"symbol":    order.InstId,
+		"qty":       qty,
+		"price":     price,
+		"avgPrice":  avgPrice,
+		"execQty":   execQty,
+		"execValue": execValue,
+		"fee":       fee,
+		"isClosed":  isClosed,
+		"createdAt": createdAt,
+		"updatedAt": updatedAt,
+	}
+
+	return orderData, nil
+}
+
+var (
+	_ broker.Broker      = (*BrokerImpl)(nil)
+	_ cdl.CandleProvider = (*Client)(nil)
+)
@@ -0,0 +1,190 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/broker/okx/models"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+)
+
+const (
+	candlesLimit        = 300              // максимальное количество последних свечей в одном запросе
+	historyCandlesLimit = 100              // максимальное количество исторических свечей в одном запросе
+	streamPingInterval  = 25 * time.Second // интервал "ping", биржа закрывает соединение через 30 с
+)
+
+// GetInstrumentInfo возвращает информацию о торговом инструменте по его ID.
+// Результат кэшируется для пересчета количества в контракты.
+// https://www.okx.com/docs-v5/en/#public-data-rest-api-get-instruments
+func (c *Client) GetInstrumentInfo(instId string) (*models.Instrument, error) {
+	query := make(url.Values)
+	query.Set("instType", c.instType)
+	query.Set("instId", instId)
+	var instruments []models.Instrument
+	if err := c.callAPI(http.MethodGet, "/api/v5/public/instruments", query, nil, false, &instruments); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetInstrumentInfo")
+	}
+	if len(instruments) == 0 {
+		err := fmt.Errorf("instrument %s not found", instId)
+		return nil, NewError(InternalErrorT, err).SetEndpoint("GetInstrumentInfo")
+	}
+	instrument := &instruments[0]
+
+	c.instMu.Lock()
+	c.instruments[instId] = instrument
+	c.instMu.Unlock()
+
+	return instrument, nil
+}
+
+// GetTicker возвращает последние рыночные данные инструмента.
+// https://www.okx.com/docs-v5/en/#order-book-trading-market-data-get-ticker
+func (c *Client) GetTicker(instId string) (*models.Ticker, error) {
+	query := make(url.Values)
+	query.Set("instId", instId)
+	var tickers []models.Ticker
+	if err := c.callAPI(http.MethodGet, "/api/v5/market/ticker", query, nil, false, &tickers); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetTicker")
+	}
+	if len(tickers) == 0 {
+		err := fmt.Errorf("ticker %s not found", instId)
+		return nil, NewError(InternalErrorT, err).SetEndpoint("GetTicker")
+	}
+
+	return &tickers[0], nil
+}
+
+// contractValue возвращает количество базовой монеты в одном контракте инструмента (1 для спота)
+func (c *Client) contractValue(instId string) (float64, error) {
+	if c.instType == "SPOT" {
+		return 1, nil
+	}
+	c.instMu.Lock()
+	instrument, ok := c.instruments[instId]
+	c.instMu.Unlock()
+	if !ok {
+		var err error
+		if instrument, err = c.GetInstrumentInfo(instId); err != nil {
+			return 0, err
+		}
+	}
+	ctVal, err := strconv.ParseFloat(instrument.CtVal, 64)
+	if err != nil || ctVal <= 0 {
+		err := fmt.Errorf("invalid contract value of %s: %q", instId, instrument.CtVal)
+		return 0, NewError(InternalErrorT, err)
+	}
+
+	return ctVal, nil
+}
+
+// GetCandles возвращает исторические свечи с ограничением по количеству. Последняя свеча не подтверждена.
+// https://www.okx.com/docs-v5/en/#order-book-trading-market-data-get-candlesticks
+// https://www.okx.com/docs-v5/en/#order-book-trading-market-data-get-candlesticks-history
+func (c *Client) GetCandles(instId string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	query := make(url.Values)
+	query.Set("instId", instId)
+	query.Set("bar", AsLocalInterval(interval))
+	query.Set("limit", strconv.Itoa(min(limit, candlesLimit)))
+	candles, err := c.getCandles("/api/v5/market/candles", query)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := limit - len(candles)
+	for counter > 0 && len(candles) > 0 {
+		nextLimit := min(historyCandlesLimit, counter)
+		query.Set("limit", strconv.Itoa(nextLimit))
+		query.Set("after", strconv.FormatInt(candles[len(candles)-1].Time, 10))
+		newCandles, err := c.getCandles("/api/v5/market/history-candles", query)
+		if err != nil {
+			return candles, err
+		}
+		if len(newCandles) == 0 {
+			break
+		}
+		candles = append(candles, newCandles...)
+		counter -= len(newCandles)
+	}
+	slices.Reverse(candles)
+
+	return candles, nil
+}
+
+// getCandles запрашивает свечи в порядке убывания времени
+func (c *Client) getCandles(path string, query url.Values) ([]cdl.Candle, error) {
+	var rawCandles [][]string
+	if err := c.callAPI(http.MethodGet, path, query, nil, false, &rawCandles); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetCandles")
+	}
+	candles := make([]cdl.Candle, len(rawCandles))
+	for i, v := range rawCandles {
+		candle, err := c.candleFromRawData(v)
+		if err != nil {
+			return nil, NewError(SerDeErrorT, err).SetEndpoint("GetCandles")
+		}
+		candles[i] = candle
+	}
+
+	return candles, nil
+}
+
+// CandleStream подписывается на поток свечей через WebSocket.
+// https://www.okx.com/docs-v5/en/#order-book-trading-market-data-ws-candlesticks-channel
+func (c *Client) CandleStream(ctx context.Context, instId string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	subscribe, _ := json.Marshal(map[string]any{
+		"op": "subscribe",
+		"args": []map[string]string{{
+			"channel": "candle" + AsLocalInterval(interval),
+			"instId":  instId,
+		}},
+	})
+	outChan, err := ws.Connect(
+		c.wsURL,
+		ctx,
+		ws.WithHandshake(subscribe),
+		ws.WithHeartbeat([]byte("ping"), streamPingInterval),
+	)
+	if err != nil {
+		return nil, NewError(InternalErrorT, err).SetEndpoint("CandleStream")
+	}
+
+	stream := make(chan *cdl.CandleStreamData)
+	go func() {
+		defer close(stream)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-outChan:
+				if !ok {
+					return
+				}
+				var candleStreamRawData models.CandleStreamRawData
+				if err := json.Unmarshal(data, &candleStreamRawData); err != nil || len(candleStreamRawData.Data) == 0 {
+					continue
+				}
+				candleStreamData, err := c.candleStreamFromRawData(candleStreamRawData.Data[0], interval)
+				if err != nil {
+					continue
+				}
+				select {
+				case stream <- candleStreamData:
+				case <-time.After(time.Second):
+					if candleStreamData.Confirm {
+						stream <- candleStreamData
+					}
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
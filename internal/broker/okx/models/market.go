@@ -0,0 +1,35 @@
+package models
+
+// Instrument содержит параметры инструмента
+type Instrument struct {
+	InstId    string `json:"instId"`    // ID инструмента, например BTC-USDT-SWAP
+	InstType  string `json:"instType"`  // Тип инструмента: SPOT, SWAP, ...
+	BaseCcy   string `json:"baseCcy"`   // Базовая монета (SPOT)
+	QuoteCcy  string `json:"quoteCcy"`  // Котируемая монета (SPOT)
+	SettleCcy string `json:"settleCcy"` // Монета расчетов (SWAP)
+	CtVal     string `json:"ctVal"`     // Стоимость контракта (SWAP)
+	CtValCcy  string `json:"ctValCcy"`  // Монета стоимости контракта (SWAP)
+	TickSz    string `json:"tickSz"`    // Шаг цены
+	LotSz     string `json:"lotSz"`     // Шаг количества (в контрактах для SWAP)
+	MinSz     string `json:"minSz"`     // Минимальное количество (в контрактах для SWAP)
+	State     string `json:"state"`     // Статус: live, suspend, preopen, ...
+}
+
+// Ticker содержит последние рыночные данные инструмента
+type Ticker struct {
+	InstId string `json:"instId"` // ID инструмента
+	Last   string `json:"last"`   // Цена последней сделки
+	BidPx  string `json:"bidPx"`  // Лучшая цена покупки
+	AskPx  string `json:"askPx"`  // Лучшая цена продажи
+	Ts     string `json:"ts"`     // Время данных (мс)
+}
+
+// CandleStreamRawData представляет потоковые данные свечей.
+// Свеча: [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm].
+type CandleStreamRawData struct {
+	Arg struct {
+		Channel string `json:"channel"` // Канал: candle1m, candle1H, ...
+		InstId  string `json:"instId"`  // ID инструмента
+	} `json:"arg"`
+	Data [][]string `json:"data"` // Свечи
+}
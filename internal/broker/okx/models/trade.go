@@ -0,0 +1,29 @@
+package models
+
+// OrderResult содержит результат операции над ордером
+type OrderResult struct {
+	OrdId   string `json:"ordId"`   // ID ордера
+	ClOrdId string `json:"clOrdId"` // Пользовательский ID ордера
+	SCode   string `json:"sCode"`   // Код результата операции ("0" - успех)
+	SMsg    string `json:"sMsg"`    // Описание ошибки операции
+}
+
+// Order содержит информацию об ордере
+type Order struct {
+	InstId    string `json:"instId"`    // ID инструмента
+	InstType  string `json:"instType"`  // Тип инструмента
+	OrdId     string `json:"ordId"`     // ID ордера
+	ClOrdId   string `json:"clOrdId"`   // Пользовательский ID ордера
+	OrdType   string `json:"ordType"`   // Тип ордера: market, limit, ...
+	Side      string `json:"side"`      // Направление сделки: buy/sell
+	TdMode    string `json:"tdMode"`    // Режим торговли: cash, cross, isolated
+	Px        string `json:"px"`        // Цена ордера
+	Sz        string `json:"sz"`        // Количество (в контрактах для SWAP)
+	AvgPx     string `json:"avgPx"`     // Средняя цена исполнения
+	AccFillSz string `json:"accFillSz"` // Исполненное количество
+	Fee       string `json:"fee"`       // Комиссия (отрицательная - списание)
+	FeeCcy    string `json:"feeCcy"`    // Монета комиссии
+	State     string `json:"state"`     // Статус: live, partially_filled, filled, canceled, mmp_canceled
+	CTime     string `json:"cTime"`     // Время создания (мс)
+	UTime     string `json:"uTime"`     // Время обновления (мс)
+}
@@ -0,0 +1,280 @@
+package okx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/okx/models"
+)
+
+const (
+	batchLimit      = 20  // максимальное количество ордеров в пакетном запросе
+	openOrdersLimit = 100 // максимальное количество активных ордеров на странице
+)
+
+// callOrderAPI выполняет запрос операции над ордерами и возвращает результаты по каждому ордеру.
+// При частичном или полном отказе (код "1" или "2") ошибки указываются в результатах ордеров.
+func (c *Client) callOrderAPI(endpoint, path string, body any, n int) ([]broker.BatchResult, error) {
+	serverResponse, err := c.do(http.MethodPost, path, nil, body, true)
+	if err != nil {
+		return nil, err.(*Error).SetEndpoint(endpoint)
+	}
+	var orderResults []models.OrderResult
+	switch serverResponse.Code {
+	case "0", "1", "2":
+		if err := json.Unmarshal(serverResponse.Data, &orderResults); err != nil {
+			return nil, NewError(SerDeErrorT, err).SetEndpoint(endpoint)
+		}
+	}
+	if serverResponse.Code != "0" && len(orderResults) == 0 {
+		return nil, ErrorFromServerResponse(serverResponse).SetEndpoint(endpoint)
+	}
+
+	results := make([]broker.BatchResult, n)
+	for i := range results {
+		if i >= len(orderResults) {
+			continue
+		}
+		if orderResults[i].SCode != "0" {
+			code, _ := strconv.Atoi(orderResults[i].SCode)
+			err := &serverResponseError{msg: orderResults[i].SMsg, code: code}
+			results[i].Err = NewError(ServerResponseErrorT, err).SetEndpoint(endpoint)
+			continue
+		}
+		results[i].OrderId = orderResults[i].OrdId
+	}
+
+	return results, nil
+}
+
+// placeOrderParams формирует параметры создания рыночного или лимитного ордера.
+// Количество базовой монеты пересчитывается в контракты для SWAP.
+func (c *Client) placeOrderParams(instId string, qty float64, price *float64) (map[string]string, error) {
+	ctVal, err := c.contractValue(instId)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"instId":  instId,
+		"tdMode":  "cross",
+		"side":    "buy",
+		"ordType": "market",
+		"sz":      contractSize(qty, ctVal),
+	}
+	if qty < 0 {
+		params["side"] = "sell"
+	}
+	if c.instType == "SPOT" {
+		// Без маржи, количество рыночного ордера всегда в базовой монете
+		params["tdMode"] = "cash"
+		params["tgtCcy"] = "base_ccy"
+	}
+	if price != nil {
+		params["ordType"] = "limit"
+		params["px"] = strconv.FormatFloat(*price, 'f', -1, 64)
+	}
+
+	return params, nil
+}
+
+// PlaceOrder создает рыночный или лимитный ордер и возвращает ID ордера вида "instId:ordId".
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-place-order
+func (c *Client) PlaceOrder(instId string, qty float64, price *float64) (string, error) {
+	params, err := c.placeOrderParams(instId, qty, price)
+	if err != nil {
+		return "", err.(*Error).SetEndpoint("PlaceOrder")
+	}
+	results, err := c.callOrderAPI("PlaceOrder", "/api/v5/trade/order", params, 1)
+	if err != nil {
+		return "", err
+	}
+	if results[0].Err != nil {
+		return "", results[0].Err
+	}
+
+	return formatOrderId(instId, results[0].OrderId), nil
+}
+
+// CancelOrder отменяет активный ордер по ID вида "instId:ordId".
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-cancel-order
+func (c *Client) CancelOrder(orderId string) (string, error) {
+	instId, ordId, err := parseOrderId(orderId)
+	if err != nil {
+		return "", err.(*Error).SetEndpoint("CancelOrder")
+	}
+	params := map[string]string{
+		"instId": instId,
+		"ordId":  ordId,
+	}
+	results, err := c.callOrderAPI("CancelOrder", "/api/v5/trade/cancel-order", params, 1)
+	if err != nil {
+		return "", err
+	}
+	if results[0].Err != nil {
+		return "", results[0].Err
+	}
+
+	return formatOrderId(instId, results[0].OrderId), nil
+}
+
+// GetOrder возвращает ордер по ID вида "instId:ordId".
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-get-order-details
+func (c *Client) GetOrder(orderId string) (*models.Order, error) {
+	instId, ordId, err := parseOrderId(orderId)
+	if err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOrder")
+	}
+	query := make(url.Values)
+	query.Set("instId", instId)
+	query.Set("ordId", ordId)
+	var orders []models.Order
+	if err := c.callAPI(http.MethodGet, "/api/v5/trade/order", query, nil, true, &orders); err != nil {
+		return nil, err.(*Error).SetEndpoint("GetOrder")
+	}
+	if len(orders) == 0 {
+		err := &serverResponseError{msg: "order does not exist"}
+		return nil, NewError(ServerResponseErrorT, err).SetEndpoint("GetOrder")
+	}
+
+	return &orders[0], nil
+}
+
+// GetOpenOrders возвращает все активные ордера инструмента (пустой instId - по всем инструментам типа),
+// последовательно запрашивая страницы.
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-get-order-list
+func (c *Client) GetOpenOrders(instId string) ([]models.Order, error) {
+	query := make(url.Values)
+	query.Set("instType", c.instType)
+	query.Set("limit", strconv.Itoa(openOrdersLimit))
+	if instId != "" {
+		query.Set("instId", instId)
+	}
+	var orders []models.Order
+	for {
+		var page []models.Order
+		if err := c.callAPI(http.MethodGet, "/api/v5/trade/orders-pending", query, nil, true, &page); err != nil {
+			return orders, err.(*Error).SetEndpoint("GetOpenOrders")
+		}
+		orders = append(orders, page...)
+		if len(page) < openOrdersLimit {
+			break
+		}
+		query.Set("after", page[len(page)-1].OrdId)
+	}
+
+	return orders, nil
+}
+
+// callBatchAPI выполняет пакетный запрос частями по batchLimit ордеров.
+// При ошибке запроса возвращаются результаты уже выполненных частей.
+func (c *Client) callBatchAPI(endpoint, path string, items []map[string]string) ([]broker.BatchResult, error) {
+	results := make([]broker.BatchResult, 0, len(items))
+	for i := 0; i < len(items); i += batchLimit {
+		chunk := items[i:min(i+batchLimit, len(items))]
+		chunkResults, err := c.callOrderAPI(endpoint, path, chunk, len(chunk))
+		if err != nil {
+			return results, err
+		}
+		for k, r := range chunkResults {
+			if r.Err == nil {
+				r.OrderId = formatOrderId(chunk[k]["instId"], r.OrderId)
+			}
+			results = append(results, r)
+		}
+	}
+
+	return results, nil
+}
+
+// BatchPlaceOrders создает ордера пакетными запросами.
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-place-multiple-orders
+func (c *Client) BatchPlaceOrders(orders []broker.OrderParams) ([]broker.BatchResult, error) {
+	items := make([]map[string]string, len(orders))
+	for i, o := range orders {
+		params, err := c.placeOrderParams(o.Symbol, o.Qty, o.Price)
+		if err != nil {
+			return nil, err.(*Error).SetEndpoint("BatchPlaceOrders")
+		}
+		items[i] = params
+	}
+
+	return c.callBatchAPI("BatchPlaceOrders", "/api/v5/trade/batch-orders", items)
+}
+
+// BatchAmendOrders изменяет ордера пакетными запросами.
+// Принимаются ID вида "instId:ordId" и ID ордеров OKX.
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-amend-multiple-orders
+func (c *Client) BatchAmendOrders(orders []broker.AmendParams) ([]broker.BatchResult, error) {
+	items := make([]map[string]string, len(orders))
+	for i, o := range orders {
+		ordId := o.OrderId
+		if _, id, err := parseOrderId(ordId); err == nil {
+			ordId = id
+		}
+		item := map[string]string{
+			"instId": o.Symbol,
+			"ordId":  ordId,
+		}
+		if o.Qty != nil {
+			ctVal, err := c.contractValue(o.Symbol)
+			if err != nil {
+				return nil, err.(*Error).SetEndpoint("BatchAmendOrders")
+			}
+			item["newSz"] = contractSize(*o.Qty, ctVal)
+		}
+		if o.Price != nil {
+			item["newPx"] = strconv.FormatFloat(*o.Price, 'f', -1, 64)
+		}
+		items[i] = item
+	}
+
+	return c.callBatchAPI("BatchAmendOrders", "/api/v5/trade/amend-batch-orders", items)
+}
+
+// BatchCancelOrders отменяет ордера инструмента пакетными запросами.
+// Принимаются ID вида "instId:ordId" и ID ордеров OKX.
+// https://www.okx.com/docs-v5/en/#order-book-trading-trade-post-cancel-multiple-orders
+func (c *Client) BatchCancelOrders(instId string, orderIds []string) ([]broker.BatchResult, error) {
+	items := make([]map[string]string, len(orderIds))
+	for i, ordId := range orderIds {
+		if _, id, err := parseOrderId(ordId); err == nil {
+			ordId = id
+		}
+		items[i] = map[string]string{
+			"instId": instId,
+			"ordId":  ordId,
+		}
+	}
+
+	return c.callBatchAPI("BatchCancelOrders", "/api/v5/trade/cancel-batch-orders", items)
+}
+
+// CancelAllOrders отменяет все активные ордера инструмента и возвращает ID отмененных ордеров.
+// OKX не предоставляет отмену всех ордеров инструмента, поэтому активные ордера
+// запрашиваются и отменяются пакетными запросами.
+func (c *Client) CancelAllOrders(instId string) ([]string, error) {
+	orders, err := c.GetOpenOrders(instId)
+	if err != nil {
+		return nil, err.(*Error).SetEndpoint("CancelAllOrders")
+	}
+	items := make([]map[string]string, len(orders))
+	for i, o := range orders {
+		items[i] = map[string]string{
+			"instId": o.InstId,
+			"ordId":  o.OrdId,
+		}
+	}
+	results, err := c.callBatchAPI("CancelAllOrders", "/api/v5/trade/cancel-batch-orders", items)
+
+	var orderIds []string
+	for _, r := range results {
+		if r.Err == nil {
+			orderIds = append(orderIds, r.OrderId)
+		}
+	}
+
+	return orderIds, err
+}
@@ -0,0 +1,107 @@
+package okx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+// AsLocalInterval преобразует cdl.Interval в локальный формат интервала.
+// Интервалы от 6 часов выравниваются по UTC, как и бары cdl.
+func AsLocalInterval(i cdl.Interval) string {
+	switch i {
+	case cdl.M1:
+		return "1m"
+	case cdl.M3:
+		return "3m"
+	case cdl.M5:
+		return "5m"
+	case cdl.M15:
+		return "15m"
+	case cdl.M30:
+		return "30m"
+	case cdl.H1:
+		return "1H"
+	case cdl.H2:
+		return "2H"
+	case cdl.H4:
+		return "4H"
+	case cdl.H6:
+		return "6Hutc"
+	case cdl.H12:
+		return "12Hutc"
+	case cdl.D1:
+		return "1Dutc"
+	case cdl.D7:
+		return "1Wutc"
+	case cdl.D30:
+		return "1Mutc"
+	}
+	return ""
+}
+
+// candleFromRawData преобразует сырую свечу [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm]
+// в структурированный формат. Объем - в базовой монете: для SWAP vol указан в контрактах,
+// а volCcy - в базовой монете.
+func (c *Client) candleFromRawData(v []string) (cdl.Candle, error) {
+	if len(v) < 9 {
+		return cdl.Candle{}, fmt.Errorf("unexpected candle length: %d", len(v))
+	}
+	volume := v[5]
+	if c.instType != "SPOT" {
+		volume = v[6]
+	}
+	rawData := [7]string{v[0], v[1], v[2], v[3], v[4], volume, v[7]}
+
+	return cdl.ParseCandleFromRawData(rawData)
+}
+
+// candleStreamFromRawData преобразует сырую свечу из WebSocket в структурированный формат.
+// Время свечи потока - время ее закрытия, как и у остальных брокеров.
+func (c *Client) candleStreamFromRawData(v []string, interval cdl.Interval) (*cdl.CandleStreamData, error) {
+	candle, err := c.candleFromRawData(v)
+	if err != nil {
+		return nil, err
+	}
+	candle.Time = interval.CloseTime(candle.Time) - 1
+
+	return &cdl.CandleStreamData{
+		Interval: interval,
+		Confirm:  v[8] == "1",
+		Candle:   candle,
+	}, nil
+}
+
+// parseFloatOrZero разбирает число, пустая строка соответствует нулю
+func parseFloatOrZero(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// contractSize пересчитывает количество базовой монеты в количество контрактов
+// стоимостью ctVal. Округление убирает погрешность деления чисел с плавающей точкой.
+func contractSize(qty, ctVal float64) string {
+	return strconv.FormatFloat(numeric.RoundFloat(math.Abs(qty)/ctVal, 8), 'f', -1, 64)
+}
+
+// formatOrderId формирует ID ордера брокера. Запросы ордеров OKX требуют ID инструмента,
+// поэтому он включается в ID: "BTC-USDT-SWAP:123".
+func formatOrderId(instId, ordId string) string {
+	return fmt.Sprintf("%s:%s", instId, ordId)
+}
+
+// parseOrderId разбирает ID ордера брокера на ID инструмента и ID ордера OKX
+func parseOrderId(orderId string) (string, string, error) {
+	instId, ordId, ok := strings.Cut(orderId, ":")
+	if !ok || instId == "" || ordId == "" {
+		return "", "", NewError(InternalErrorT, fmt.Errorf("invalid order id: %q", orderId))
+	}
+
+	return instId, ordId, nil
+}
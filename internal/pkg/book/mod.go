@@ -0,0 +1,270 @@
+package book
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Level - ценовой уровень книги ордеров
+type Level struct {
+	Price float64 `json:"price"` // Цена уровня
+	Size  float64 `json:"size"`  // Объем уровня
+}
+
+// Update - снимок или изменение книги ордеров от поставщика
+type Update struct {
+	Symbol   string  // Название торговой пары
+	Snapshot bool    // Полный снимок, заменяющий локальную книгу
+	Bids     []Level // Уровни покупки, нулевой объем удаляет уровень
+	Asks     []Level // Уровни продажи, нулевой объем удаляет уровень
+	UpdateID int64   // Идентификатор обновления
+	Seq      int64   // Кросс-последовательность биржи
+	Time     int64   // Время обновления (мс)
+}
+
+// OrderBookProvider определяет интерфейс поставщика потока книги ордеров.
+// Поставщик отвечает за проверку последовательности обновлений и повторную
+// синхронизацию: после разрыва последовательности он отправляет новый снимок.
+type OrderBookProvider interface {
+	OrderBookStream(ctx context.Context, symbol string, depth int) (<-chan *Update, error)
+}
+
+// OrderBook - локальная книга ордеров. Безопасна для использования из нескольких горутин.
+type OrderBook struct {
+	Symbol   string
+	bids     map[float64]float64
+	asks     map[float64]float64
+	updateID int64
+	seq      int64
+	time     int64
+	ready    bool
+	mu       sync.RWMutex
+}
+
+func NewOrderBook(symbol string) *OrderBook {
+	return &OrderBook{
+		Symbol: symbol,
+		bids:   make(map[float64]float64),
+		asks:   make(map[float64]float64),
+	}
+}
+
+// Start создает книгу ордеров и поддерживает ее в актуальном состоянии
+// по потоку поставщика до завершения контекста
+func Start(ctx context.Context, provider OrderBookProvider, symbol string, depth int) (*OrderBook, error) {
+	stream, err := provider.OrderBookStream(ctx, symbol, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewOrderBook(symbol)
+	go func() {
+		for u := range stream {
+			if u != nil {
+				b.Apply(u)
+			}
+		}
+	}()
+
+	return b, nil
+}
+
+// Apply применяет снимок или изменение. Изменения до первого снимка игнорируются.
+func (b *OrderBook) Apply(u *Update) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if u.Snapshot {
+		clear(b.bids)
+		clear(b.asks)
+		b.ready = true
+	} else if !b.ready {
+		return
+	}
+	applyLevels(b.bids, u.Bids)
+	applyLevels(b.asks, u.Asks)
+	b.updateID = u.UpdateID
+	b.seq = u.Seq
+	b.time = u.Time
+}
+
+func applyLevels(side map[float64]float64, levels []Level) {
+	for _, l := range levels {
+		if l.Size == 0 {
+			delete(side, l.Price)
+		} else {
+			side[l.Price] = l.Size
+		}
+	}
+}
+
+// Ready сообщает, что книга получила снимок
+func (b *OrderBook) Ready() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.ready
+}
+
+// UpdateID возвращает идентификатор последнего примененного обновления
+func (b *OrderBook) UpdateID() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.updateID
+}
+
+// Time возвращает время последнего обновления (мс)
+func (b *OrderBook) Time() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.time
+}
+
+// sortedLevels возвращает до n лучших уровней стороны (n < 0 - все уровни)
+func sortedLevels(side map[float64]float64, n int, desc bool) []Level {
+	levels := make([]Level, 0, len(side))
+	for price, size := range side {
+		levels = append(levels, Level{Price: price, Size: size})
+	}
+	slices.SortFunc(levels, func(a, b Level) int {
+		if desc {
+			a, b = b, a
+		}
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	})
+	if n >= 0 && n < len(levels) {
+		levels = levels[:n]
+	}
+	return levels
+}
+
+// Bids возвращает до n лучших уровней покупки по убыванию цены (n < 0 - все уровни)
+func (b *OrderBook) Bids(n int) []Level {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return sortedLevels(b.bids, n, true)
+}
+
+// Asks возвращает до n лучших уровней продажи по возрастанию цены (n < 0 - все уровни)
+func (b *OrderBook) Asks(n int) []Level {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return sortedLevels(b.asks, n, false)
+}
+
+// BestBid возвращает лучший уровень покупки
+func (b *OrderBook) BestBid() (Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return bestLevel(b.bids, true)
+}
+
+// BestAsk возвращает лучший уровень продажи
+func (b *OrderBook) BestAsk() (Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return bestLevel(b.asks, false)
+}
+
+func bestLevel(side map[float64]float64, highest bool) (Level, bool) {
+	var best Level
+	found := false
+	for price, size := range side {
+		if !found || (highest && price > best.Price) || (!highest && price < best.Price) {
+			best = Level{Price: price, Size: size}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// bestPrices возвращает лучшие цены покупки и продажи, прочитанные под одной блокировкой
+func (b *OrderBook) bestPrices() (bid, ask Level, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bid, okBid := bestLevel(b.bids, true)
+	ask, okAsk := bestLevel(b.asks, false)
+	return bid, ask, okBid && okAsk
+}
+
+// Spread возвращает разницу лучших цен продажи и покупки (0, если сторона пуста)
+func (b *OrderBook) Spread() float64 {
+	bid, ask, ok := b.bestPrices()
+	if !ok {
+		return 0
+	}
+	return ask.Price - bid.Price
+}
+
+// Mid возвращает среднюю цену между лучшими ценами (0, если сторона пуста)
+func (b *OrderBook) Mid() float64 {
+	bid, ask, ok := b.bestPrices()
+	if !ok {
+		return 0
+	}
+	return (ask.Price + bid.Price) / 2
+}
+
+// SizeAt возвращает объем уровня с ценой price на любой стороне
+func (b *OrderBook) SizeAt(price float64) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bids[price] + b.asks[price]
+}
+
+// DepthAt возвращает суммарный объем уровней от лучшей цены до price включительно:
+// по стороне покупки для цен не выше лучшей покупки и по стороне продажи - для остальных
+func (b *OrderBook) DepthAt(price float64) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var depth float64
+	if bid, ok := bestLevel(b.bids, true); ok && price <= bid.Price {
+		for p, size := range b.bids {
+			if p >= price {
+				depth += size
+			}
+		}
+		return depth
+	}
+	for p, size := range b.asks {
+		if p <= price {
+			depth += size
+		}
+	}
+	return depth
+}
+
+// Imbalance возвращает дисбаланс объемов levels лучших уровней в диапазоне [-1, 1]:
+// положительный при преобладании покупок (levels <= 0 - все уровни)
+func (b *OrderBook) Imbalance(levels int) float64 {
+	if levels <= 0 {
+		levels = -1
+	}
+	var bidVolume, askVolume float64
+	for _, l := range b.Bids(levels) {
+		bidVolume += l.Size
+	}
+	for _, l := range b.Asks(levels) {
+		askVolume += l.Size
+	}
+	if total := bidVolume + askVolume; total != 0 {
+		return (bidVolume - askVolume) / total
+	}
+	return 0
+}
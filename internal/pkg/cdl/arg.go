@@ -1,6 +1,7 @@
 package cdl
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -40,6 +41,24 @@ const (
 	ShadowRatio        CandleArg = "SR"       // Соотношение теней к телу (UW+LW)/Body
 )
 
+// candleArgs - список всех параметров свечи
+var candleArgs = []CandleArg{
+	Time, Open, High, Low, Close, CL, CH, HL, HLC, OHLC, HLCC, Volume, Turnover,
+	TrueRange, NormalizedRange, RateOfChange, Momentum, Acceleration, PriceVolume,
+	Body, UpperWick, LowerWick, WickRatio, BodyRangeRatio, Direction, WeightedClose,
+	VWAP, CloseLocationValue, ShadowRatio,
+}
+
+// ParseCandleArg возвращает параметр свечи по его строковому обозначению
+func ParseCandleArg(s string) (CandleArg, error) {
+	for _, a := range candleArgs {
+		if string(a) == s {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("invalid candle arg: %s", s)
+}
+
 // ListOfCandleArg возвращает список значений указанного параметра свечей
 func ListOfCandleArg(candles []Candle, arg CandleArg) []float64 {
 	list := make([]float64, len(candles))
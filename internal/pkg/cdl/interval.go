@@ -2,7 +2,11 @@ package cdl
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Interval uint16
@@ -23,6 +27,28 @@ const (
 	D30 Interval = 43200
 )
 
+// nativeIntervals - интервалы, которые биржа отдает напрямую
+var nativeIntervals = []Interval{M1, M3, M5, M15, M30, H1, H2, H4, H6, H12, D1, D7, D30}
+
+// IsNative сообщает, что интервал предоставляется биржей без пересборки
+func (i Interval) IsNative() bool {
+	return slices.Contains(nativeIntervals, i)
+}
+
+// Base возвращает наибольший стандартный интервал, из свечей которого
+// можно собрать свечи интервала i. Для стандартных интервалов возвращает i.
+func (i Interval) Base() Interval {
+	if i.IsNative() {
+		return i
+	}
+	for j := len(nativeIntervals) - 1; j >= 0; j-- {
+		if base := nativeIntervals[j]; base <= D1 && i%base == 0 {
+			return base
+		}
+	}
+	return M1
+}
+
 func (i Interval) AsSeconds() int {
 	return int(i) * 60
 }
@@ -31,6 +57,32 @@ func (i Interval) AsMilli() int {
 	return i.AsSeconds() * 1000
 }
 
+// weekOffsetMs смещает недельные бары: 1970-01-01 - четверг, а неделя начинается с понедельника
+const weekOffsetMs = 4 * 24 * 60 * 60 * 1000
+
+// OpenTime возвращает время открытия (мс) бара интервала, содержащего момент ms.
+// Месячные бары начинаются с первого числа месяца UTC, недельные - с понедельника.
+func (i Interval) OpenTime(ms int64) int64 {
+	step := int64(i.AsMilli())
+	switch i {
+	case D30:
+		t := time.UnixMilli(ms).UTC()
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	case D7:
+		return ms - ((ms-weekOffsetMs)%step+step)%step
+	}
+	return ms - (ms%step+step)%step
+}
+
+// CloseTime возвращает время закрытия (мс) бара, открытого в момент openTime
+func (i Interval) CloseTime(openTime int64) int64 {
+	if i == D30 {
+		t := time.UnixMilli(i.OpenTime(openTime)).UTC()
+		return t.AddDate(0, 1, 0).UnixMilli()
+	}
+	return i.OpenTime(openTime) + int64(i.AsMilli())
+}
+
 func (i Interval) AsString() string {
 	switch i {
 	case M1:
@@ -59,8 +111,17 @@ func (i Interval) AsString() string {
 		return "D7"
 	case D30:
 		return "D30"
-	default:
+	}
+	// Произвольные интервалы, собираемые из стандартных
+	switch {
+	case i == 0:
 		return ""
+	case i%D1 == 0:
+		return fmt.Sprintf("D%d", i/D1)
+	case i%H1 == 0:
+		return fmt.Sprintf("H%d", i/H1)
+	default:
+		return fmt.Sprintf("M%d", i)
 	}
 }
 
@@ -99,6 +160,24 @@ func ParseInterval(v any) (Interval, error) {
 	case "D30", "43200", "M":
 		return D30, nil
 	default:
+		return parseCustomInterval(s, v)
+	}
+}
+
+// parseCustomInterval разбирает произвольный интервал вида M10, H8, D2 или число минут
+func parseCustomInterval(s string, v any) (Interval, error) {
+	unit := 1
+	switch {
+	case strings.HasPrefix(s, "M"):
+		s = s[1:]
+	case strings.HasPrefix(s, "H"):
+		unit, s = int(H1), s[1:]
+	case strings.HasPrefix(s, "D"):
+		unit, s = int(D1), s[1:]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 || n*unit > math.MaxUint16 {
 		return 0, fmt.Errorf("invalid interval: %v", v)
 	}
+	return Interval(n * unit), nil
 }
@@ -0,0 +1,230 @@
+package cdl
+
+import "fmt"
+
+// CandlePattern - тип свечного паттерна
+type CandlePattern string
+
+// Константы свечных паттернов
+const (
+	Doji               CandlePattern = "Doji"               // Доджи: тело почти отсутствует
+	Hammer             CandlePattern = "Hammer"             // Молот: длинная нижняя тень после снижения
+	ShootingStar       CandlePattern = "ShootingStar"       // Падающая звезда: длинная верхняя тень после роста
+	BullishEngulfing   CandlePattern = "BullishEngulfing"   // Бычье поглощение
+	BearishEngulfing   CandlePattern = "BearishEngulfing"   // Медвежье поглощение
+	BullishHarami      CandlePattern = "BullishHarami"      // Бычий харами
+	BearishHarami      CandlePattern = "BearishHarami"      // Медвежий харами
+	MorningStar        CandlePattern = "MorningStar"        // Утренняя звезда
+	EveningStar        CandlePattern = "EveningStar"        // Вечерняя звезда
+	ThreeWhiteSoldiers CandlePattern = "ThreeWhiteSoldiers" // Три белых солдата
+	ThreeBlackCrows    CandlePattern = "ThreeBlackCrows"    // Три черные вороны
+	InsideBar          CandlePattern = "InsideBar"          // Внутренний бар
+	OutsideBar         CandlePattern = "OutsideBar"         // Внешний бар
+)
+
+// candlePatterns - список всех свечных паттернов
+var candlePatterns = []CandlePattern{
+	Doji, Hammer, ShootingStar, BullishEngulfing, BearishEngulfing,
+	BullishHarami, BearishHarami, MorningStar, EveningStar,
+	ThreeWhiteSoldiers, ThreeBlackCrows, InsideBar, OutsideBar,
+}
+
+const (
+	dojiBodyRatio = .1 // Максимальное отношение тела к диапазону для доджи
+	trendLookback = 3  // Глубина проверки предшествующего тренда
+)
+
+// PatternSignal - сигнал паттерна, завершившегося на свече Index
+type PatternSignal struct {
+	Pattern   CandlePattern `json:"pattern"`   // Паттерн
+	Index     int           `json:"index"`     // Индекс последней свечи паттерна
+	Direction float64       `json:"direction"` // 1 (бычий), -1 (медвежий), 0 (нейтральный)
+	Strength  float64       `json:"strength"`  // Сила сигнала в диапазоне (0, 1]
+}
+
+// ParseCandlePattern возвращает паттерн по его строковому обозначению
+func ParseCandlePattern(s string) (CandlePattern, error) {
+	for _, p := range candlePatterns {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid candle pattern: %s", s)
+}
+
+// Direction возвращает направление паттерна
+func (p CandlePattern) Direction() float64 {
+	switch p {
+	case Hammer, BullishEngulfing, BullishHarami, MorningStar, ThreeWhiteSoldiers:
+		return 1
+	case ShootingStar, BearishEngulfing, BearishHarami, EveningStar, ThreeBlackCrows:
+		return -1
+	}
+	return 0
+}
+
+// DetectPattern возвращает силу паттерна p, завершившегося на свече candles[i],
+// или 0, если паттерн не обнаружен
+func DetectPattern(candles []Candle, i int, p CandlePattern) float64 {
+	if i < 0 || i >= len(candles) {
+		return 0
+	}
+	c := &candles[i]
+	var pc *Candle
+	if i > 0 {
+		pc = &candles[i-1]
+	}
+
+	switch p {
+	case Doji:
+		if c.H != c.L {
+			if r := c.Arg(BodyRangeRatio); r <= dojiBodyRatio {
+				return clamp01(1 - r/dojiBodyRatio)
+			}
+		}
+	case Hammer:
+		if priorTrend(candles, i) < 0 {
+			return wickStrength(c, c.Arg(LowerWick), c.Arg(UpperWick))
+		}
+	case ShootingStar:
+		if priorTrend(candles, i) > 0 {
+			return wickStrength(c, c.Arg(UpperWick), c.Arg(LowerWick))
+		}
+	case BullishEngulfing, BearishEngulfing:
+		dir := p.Direction()
+		if pc != nil && c.Arg(Direction) == dir && pc.Arg(Direction) == -dir &&
+			max(c.O, c.C) >= max(pc.O, pc.C) && min(c.O, c.C) <= min(pc.O, pc.C) {
+			if r := c.Ratio(BodyStrengthRatio, pc); r > 1 {
+				return clamp01(r - 1)
+			}
+		}
+	case BullishHarami, BearishHarami:
+		dir := p.Direction()
+		if pc != nil && c.Arg(Direction) == dir && pc.Arg(Direction) == -dir &&
+			max(c.O, c.C) < max(pc.O, pc.C) && min(c.O, c.C) > min(pc.O, pc.C) {
+			return clamp01(1 - c.Ratio(BodyStrengthRatio, pc))
+		}
+	case MorningStar, EveningStar:
+		if i >= 2 {
+			return starStrength(&candles[i-2], pc, c, p.Direction())
+		}
+	case ThreeWhiteSoldiers, ThreeBlackCrows:
+		if i >= 2 {
+			return threeStrength(candles[i-2:i+1], p.Direction())
+		}
+	case InsideBar:
+		if pc != nil && c.H <= pc.H && c.L >= pc.L && (c.H < pc.H || c.L > pc.L) {
+			return clamp01(1 - c.Arg(TrueRange)/pc.Arg(TrueRange))
+		}
+	case OutsideBar:
+		if pc != nil && c.H > pc.H && c.L < pc.L && pc.H != pc.L {
+			return clamp01(c.Arg(TrueRange)/pc.Arg(TrueRange) - 1)
+		}
+	}
+	return 0
+}
+
+// DetectPatterns возвращает все паттерны, завершившиеся на свече candles[i]
+func DetectPatterns(candles []Candle, i int) []PatternSignal {
+	var signals []PatternSignal
+	for _, p := range candlePatterns {
+		if s := DetectPattern(candles, i, p); s > 0 {
+			dir := p.Direction()
+			if p == OutsideBar {
+				dir = candles[i].Arg(Direction)
+			}
+			signals = append(signals, PatternSignal{
+				Pattern:   p,
+				Index:     i,
+				Direction: dir,
+				Strength:  s,
+			})
+		}
+	}
+	return signals
+}
+
+// ScanPatterns возвращает сигналы паттернов для всех свечей
+func ScanPatterns(candles []Candle) []PatternSignal {
+	var signals []PatternSignal
+	for i := range candles {
+		signals = append(signals, DetectPatterns(candles, i)...)
+	}
+	return signals
+}
+
+// ListOfCandlePattern возвращает силу паттерна для каждой свечи со знаком
+// его направления (нейтральные паттерны положительны), например, в качестве признака модели
+func ListOfCandlePattern(candles []Candle, p CandlePattern) []float64 {
+	dir := p.Direction()
+	if dir == 0 {
+		dir = 1
+	}
+
+	list := make([]float64, len(candles))
+	for i := range candles {
+		list[i] = dir * DetectPattern(candles, i, p)
+	}
+
+	return list
+}
+
+// priorTrend возвращает знак изменения Close за trendLookback свечей до свечи i
+func priorTrend(candles []Candle, i int) float64 {
+	if i <= trendLookback {
+		return 0
+	}
+	d := candles[i-1].C - candles[i-1-trendLookback].C
+	if d > 0 {
+		return 1
+	} else if d < 0 {
+		return -1
+	}
+	return 0
+}
+
+// wickStrength оценивает свечу с длинной тенью long и короткой противоположной тенью short
+func wickStrength(c *Candle, long, short float64) float64 {
+	tr := c.Arg(TrueRange)
+	body := c.Arg(Body)
+	if tr == 0 || long < 2*body || short > max(body, .1*tr) {
+		return 0
+	}
+	return clamp01((long/tr - .5) * 2)
+}
+
+// starStrength оценивает звезду: большая свеча против направления dir, малое тело
+// и свеча по направлению dir, закрывшаяся за серединой тела первой свечи
+func starStrength(first, star, last *Candle, dir float64) float64 {
+	firstBody := first.Arg(Body)
+	if first.Arg(Direction) != -dir || last.Arg(Direction) != dir ||
+		first.Arg(BodyRangeRatio) < .5 || star.Arg(Body) > .3*firstBody {
+		return 0
+	}
+	mid := (first.O + first.C) / 2
+	return clamp01(dir * (last.C - mid) / (firstBody / 2))
+}
+
+// threeStrength оценивает три последовательные свечи направления dir,
+// каждая из которых открывается внутри тела предыдущей
+func threeStrength(candles []Candle, dir float64) float64 {
+	var strength float64
+	for k := range candles {
+		c := &candles[k]
+		if c.Arg(Direction) != dir {
+			return 0
+		}
+		if k > 0 {
+			pc := &candles[k-1]
+			if dir*(c.C-pc.C) <= 0 || c.O < min(pc.O, pc.C) || c.O > max(pc.O, pc.C) {
+				return 0
+			}
+		}
+		strength += c.Arg(BodyRangeRatio)
+	}
+	return strength / float64(len(candles))
+}
+
+func clamp01(v float64) float64 {
+	return min(1, max(0, v))
+}
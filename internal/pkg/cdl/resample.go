@@ -0,0 +1,201 @@
+package cdl
+
+import (
+	"context"
+	"fmt"
+)
+
+// checkResample проверяет, что свечи интервала to собираются из целых свечей интервала from
+func checkResample(from, to Interval) error {
+	if from == 0 || from == D7 || from == D30 || to <= from || to%from != 0 {
+		return fmt.Errorf("interval %s cannot be resampled from %s", to.AsString(), from.AsString())
+	}
+	return nil
+}
+
+// mergeCandles объединяет свечу b, следующую во времени, с агрегатом a
+func mergeCandles(a, b Candle) Candle {
+	a.H = max(a.H, b.H)
+	a.L = min(a.L, b.L)
+	a.C = b.C
+	a.Volume += b.Volume
+	a.Turnover += b.Turnover
+	return a
+}
+
+// bucket - агрегат свечей младшего интервала внутри одного бара старшего
+type bucket struct {
+	candle   Candle
+	first    int64 // Время открытия первой свечи младшего интервала
+	lastOpen int64 // Время открытия последней свечи младшего интервала
+}
+
+// groupCandles группирует свечи интервала from по барам интервала to.
+// Время сгруппированной свечи - время открытия бара to.
+func groupCandles(candles []Candle, from, to Interval) []bucket {
+	var buckets []bucket
+	for _, c := range candles {
+		open := from.OpenTime(c.Time)
+		bucketOpen := to.OpenTime(open)
+		if n := len(buckets); n > 0 && buckets[n-1].candle.Time == bucketOpen {
+			buckets[n-1].candle = mergeCandles(buckets[n-1].candle, c)
+			buckets[n-1].lastOpen = open
+			continue
+		}
+		c.Time = bucketOpen
+		buckets = append(buckets, bucket{candle: c, first: open, lastOpen: open})
+	}
+	return buckets
+}
+
+// Resample строит свечи интервала to из свечей младшего интервала from.
+// Возвращаются только завершенные бары: неполные бары в начале и в конце отбрасываются.
+func Resample(candles []Candle, from, to Interval) ([]Candle, error) {
+	if err := checkResample(from, to); err != nil {
+		return nil, err
+	}
+
+	buckets := groupCandles(candles, from, to)
+	result := make([]Candle, 0, len(buckets))
+	for _, b := range buckets {
+		if b.first != b.candle.Time || from.CloseTime(b.lastOpen) != to.CloseTime(b.candle.Time) {
+			continue
+		}
+		result = append(result, b.candle)
+	}
+	return result, nil
+}
+
+// Resampler - поставщик свечей произвольных интервалов, собираемых из свечей
+// базового интервала источника. Источником может быть другой CandleSync,
+// тогда поток старшего интервала использует его подписку.
+type Resampler struct {
+	source CandleProvider
+	base   Interval
+}
+
+func NewResampler(source CandleProvider, base Interval) *Resampler {
+	return &Resampler{
+		source: source,
+		base:   base,
+	}
+}
+
+// baseCount возвращает наибольшее число базовых свечей в одном баре интервала
+func (r *Resampler) baseCount(interval Interval) int {
+	if interval == D30 {
+		return 31 * int(D1) / int(r.base)
+	}
+	return int(interval / r.base)
+}
+
+// GetCandles возвращает исторические свечи интервала. Как и у биржи,
+// последняя свеча соответствует текущему неподтвержденному бару.
+func (r *Resampler) GetCandles(symbol string, interval Interval, limit int) ([]Candle, error) {
+	if interval == r.base {
+		return r.source.GetCandles(symbol, interval, limit)
+	}
+	if err := checkResample(r.base, interval); err != nil {
+		return nil, err
+	}
+
+	count := r.baseCount(interval)
+	candles, err := r.source.GetCandles(symbol, r.base, (limit+1)*count)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := groupCandles(candles, r.base, interval)
+	if len(buckets) > 0 && buckets[0].first != buckets[0].candle.Time {
+		buckets = buckets[1:]
+	}
+	result := make([]Candle, 0, min(limit, len(buckets)))
+	for _, b := range buckets[max(0, len(buckets)-limit):] {
+		result = append(result, b.candle)
+	}
+	return result, nil
+}
+
+// CandleStream строит поток свечей интервала из потока базового интервала.
+// Бар подтверждается вместе с последней базовой свечой, закрывающей его.
+// Время свечи в потоке, как и у биржи, - время окончания бара.
+func (r *Resampler) CandleStream(ctx context.Context, symbol string, interval Interval) (<-chan *CandleStreamData, error) {
+	if interval == r.base {
+		return r.source.CandleStream(ctx, symbol, interval)
+	}
+	if err := checkResample(r.base, interval); err != nil {
+		return nil, err
+	}
+	stream, err := r.source.CandleStream(ctx, symbol, r.base)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *CandleStreamData)
+	go func() {
+		defer close(out)
+
+		var (
+			bucketOpen int64  = -1
+			acc        Candle // Агрегат подтвержденных базовых свечей бара
+			hasAcc     bool
+			seeded     bool
+		)
+		for data := range stream {
+			if data == nil {
+				continue
+			}
+			baseOpen := r.base.OpenTime(data.Candle.Time)
+			open := interval.OpenTime(baseOpen)
+			if open != bucketOpen {
+				bucketOpen, hasAcc = open, false
+				// Подписка началась посреди бара: добираем его начало из истории
+				if !seeded {
+					seeded = true
+					history, err := r.source.GetCandles(symbol, r.base, r.baseCount(interval)+1)
+					if err == nil {
+						for _, c := range history {
+							if t := r.base.OpenTime(c.Time); t >= open && t < baseOpen {
+								acc, hasAcc = r.mergeAcc(acc, hasAcc, c, open), true
+							}
+						}
+					}
+				}
+			}
+
+			candle := data.Candle
+			if hasAcc {
+				candle = mergeCandles(acc, candle)
+			}
+			candle.Time = interval.CloseTime(open) - 1
+			if data.Confirm {
+				acc, hasAcc = candle, true
+			}
+
+			closing := r.base.CloseTime(baseOpen) == interval.CloseTime(open)
+			streamData := &CandleStreamData{
+				Candle:   candle,
+				Interval: interval,
+				Confirm:  data.Confirm && closing,
+			}
+			if streamData.Confirm {
+				bucketOpen, hasAcc = -1, false
+			}
+			select {
+			case out <- streamData:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (r *Resampler) mergeAcc(acc Candle, hasAcc bool, c Candle, open int64) Candle {
+	if !hasAcc {
+		c.Time = open
+		return c
+	}
+	return mergeCandles(acc, c)
+}
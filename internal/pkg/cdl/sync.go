@@ -2,6 +2,7 @@ package cdl
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -15,6 +16,12 @@ type CandleProvider interface {
 	GetCandles(symbol string, interval Interval, limit int) ([]Candle, error)
 }
 
+// ReconnectNotifier - необязательный интерфейс поставщика, сообщающий о восстановлении
+// потока после разрыва соединения. CandleSync использует его для догрузки пропущенных свечей.
+type ReconnectNotifier interface {
+	StreamReconnects(ctx context.Context) (<-chan struct{}, error)
+}
+
 // subscriber содержит каналы для подписчика свечных данных
 type subscriber struct {
 	ch   chan<- *CandleStreamData
@@ -36,6 +43,7 @@ type CandleSync struct {
 	subscribers  map[string]subscriber
 	subRWMu      sync.RWMutex
 	stream       <-chan *CandleStreamData
+	backfill     chan *Candle
 }
 
 // NewCandleSync создает новый экземпляр CandleSync
@@ -54,6 +62,7 @@ func NewCandleSync(ctx context.Context, symbol string, interval Interval, buffer
 		writeConfirm: make(chan *Candle),
 		sendToSubs:   make(chan *CandleStreamData, 2),
 		subscribers:  make(map[string]subscriber),
+		backfill:     make(chan *Candle),
 	}
 }
 
@@ -89,9 +98,34 @@ func (s *CandleSync) StartSync() error {
 		s.writeConfirm <- &candles[0]
 	}()
 
+	if notifier, ok := s.provider.(ReconnectNotifier); ok {
+		if reconnects, err := notifier.StreamReconnects(s.ctx); err == nil {
+			go s.backfillOnReconnect(reconnects)
+		}
+	}
+
 	return nil
 }
 
+// backfillOnReconnect после восстановления потока передает последнюю подтвержденную свечу
+// поставщика в confirmWriter, который догружает свечи, пропущенные во время разрыва
+func (s *CandleSync) backfillOnReconnect(reconnects <-chan struct{}) {
+	for range reconnects {
+		candles, err := s.provider.GetCandles(s.Symbol, s.Interval, 2)
+		if err != nil || len(candles) < 2 {
+			continue
+		}
+		// Время свечи приводится к формату подтвержденных свечей потока
+		candle := candles[0]
+		candle.Time = s.Interval.CloseTime(candle.Time) - 1
+		select {
+		case s.backfill <- &candle:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
 // Subscribe добавляет нового подписчика на свечные данные
 func (s *CandleSync) Subscribe(ch chan<- *CandleStreamData) chan<- struct{} {
 	s.subRWMu.Lock()
@@ -104,6 +138,29 @@ func (s *CandleSync) Subscribe(ch chan<- *CandleStreamData) chan<- struct{} {
 	return done
 }
 
+// GetCandles возвращает исторические свечи поставщика синхронизации
+func (s *CandleSync) GetCandles(symbol string, interval Interval, limit int) ([]Candle, error) {
+	return s.provider.GetCandles(symbol, interval, limit)
+}
+
+// CandleStream подписывается на поток синхронизации, что позволяет использовать
+// CandleSync как поставщика свечей, например, в качестве источника Resampler
+func (s *CandleSync) CandleStream(ctx context.Context, symbol string, interval Interval) (<-chan *CandleStreamData, error) {
+	if symbol != s.Symbol || interval != s.Interval {
+		return nil, fmt.Errorf("candle sync %s %s cannot stream %s %s",
+			s.Symbol, s.Interval.AsString(), symbol, interval.AsString())
+	}
+
+	ch := make(chan *CandleStreamData, 8)
+	done := s.Subscribe(ch)
+	go func() {
+		<-ctx.Done()
+		close(done)
+	}()
+
+	return ch, nil
+}
+
 // removeSubscriber удаляет подписчика по ключу
 func (s *CandleSync) removeSubscriber(key string) {
 	s.subRWMu.Lock()
@@ -173,15 +230,24 @@ func (s *CandleSync) subMessenger() {
 func (s *CandleSync) streamProcessor() {
 	defer s.close()
 
-	for data := range s.stream {
-		if data == nil {
-			continue
-		}
-		if data.Confirm {
+	for {
+		select {
+		case candle := <-s.backfill:
 			s.confirmWg.Add(1)
-			s.writeConfirm <- &data.Candle
+			s.writeConfirm <- candle
+		case data, ok := <-s.stream:
+			if !ok {
+				return
+			}
+			if data == nil {
+				continue
+			}
+			if data.Confirm {
+				s.confirmWg.Add(1)
+				s.writeConfirm <- &data.Candle
+			}
+			s.sendToSubs <- data
 		}
-		s.sendToSubs <- data
 	}
 }
 
@@ -0,0 +1,386 @@
+package cdl
+
+import "context"
+
+// BarBuilder строит производные бары из подтвержденных свечей
+type BarBuilder interface {
+	// Update учитывает подтвержденную свечу и возвращает завершенные ею бары
+	Update(c Candle) []Candle
+	// Current возвращает формирующийся бар, если он есть
+	Current() (Candle, bool)
+	// Clone возвращает независимую копию построителя
+	Clone() BarBuilder
+}
+
+// Transform строит производные бары из массива подтвержденных свечей
+func Transform(candles []Candle, b BarBuilder) []Candle {
+	var bars []Candle
+	for _, c := range candles {
+		bars = append(bars, b.Update(c)...)
+	}
+	return bars
+}
+
+// TransformStream строит поток производных баров. Завершенные бары отправляются
+// с Confirm = true. Неподтвержденная свеча дает предварительный бар с Confirm = false,
+// не изменяя состояние построителя.
+func TransformStream(ctx context.Context, stream <-chan *CandleStreamData, b BarBuilder) <-chan *CandleStreamData {
+	out := make(chan *CandleStreamData)
+
+	go func() {
+		defer close(out)
+
+		send := func(c Candle, interval Interval, confirm bool) bool {
+			select {
+			case out <- &CandleStreamData{Candle: c, Interval: interval, Confirm: confirm}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for data := range stream {
+			if data == nil {
+				continue
+			}
+			if data.Confirm {
+				for _, bar := range b.Update(data.Candle) {
+					if !send(bar, data.Interval, true) {
+						return
+					}
+				}
+				continue
+			}
+
+			preview := b.Clone()
+			bars := preview.Update(data.Candle)
+			if bar, ok := preview.Current(); ok {
+				bars = append(bars, bar)
+			}
+			if n := len(bars); n > 0 && !send(bars[n-1], data.Interval, false) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// SubscribeTransform подписывает канал на поток производных баров синхронизации.
+// Построитель предварительно прогревается подтвержденными свечами буфера, свечи потока,
+// уже учтенные при прогреве, пропускаются. Закрытие возвращенного канала отменяет подписку,
+// после чего ch будет закрыт.
+func (s *CandleSync) SubscribeTransform(b BarBuilder, ch chan<- *CandleStreamData) chan<- struct{} {
+	in := make(chan *CandleStreamData, 8)
+	done := s.Subscribe(in)
+
+	warmup := s.ReadConfirmCandles(-1)
+	Transform(warmup, b)
+	// Буфер и поток могут хранить время свечи как время открытия или закрытия,
+	// поэтому сравнение ведется по времени открытия
+	var lastTime int64
+	if n := len(warmup); n > 0 {
+		lastTime = s.Interval.OpenTime(warmup[n-1].Time)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	stop := make(chan struct{}, 1)
+	go func() {
+		select {
+		case <-stop:
+		case <-ctx.Done():
+		}
+		cancel()
+		close(done)
+	}()
+
+	fresh := make(chan *CandleStreamData)
+	go func() {
+		defer close(fresh)
+		for {
+			var data *CandleStreamData
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-in:
+				if !ok {
+					return
+				}
+				data = d
+			}
+			if data == nil {
+				continue
+			}
+			openTime := s.Interval.OpenTime(data.Candle.Time)
+			if openTime <= lastTime {
+				continue
+			}
+			if data.Confirm {
+				lastTime = openTime
+			}
+			select {
+			case fresh <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(ch)
+		for data := range TransformStream(ctx, fresh, b) {
+			select {
+			case ch <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// ATR вычисляет средний истинный диапазон последних period свечей
+func ATR(candles []Candle, period int) float64 {
+	n := len(candles)
+	if period <= 0 || n < 2 {
+		return 0
+	}
+	period = min(period, n-1)
+
+	var sum float64
+	for i := n - period; i < n; i++ {
+		sum += candles[i].Ratio(TrueRangeRatio, &candles[i-1])
+	}
+	return sum / float64(period)
+}
+
+// HeikinAshi строит бары Heikin-Ashi
+type HeikinAshi struct {
+	prev    Candle
+	hasPrev bool
+}
+
+func NewHeikinAshi() *HeikinAshi {
+	return &HeikinAshi{}
+}
+
+func (h *HeikinAshi) Update(c Candle) []Candle {
+	bar := c
+	bar.C = (c.O + c.H + c.L + c.C) / 4
+	if h.hasPrev {
+		bar.O = (h.prev.O + h.prev.C) / 2
+	} else {
+		bar.O = (c.O + c.C) / 2
+	}
+	bar.H = max(c.H, bar.O, bar.C)
+	bar.L = min(c.L, bar.O, bar.C)
+
+	h.prev, h.hasPrev = bar, true
+	return []Candle{bar}
+}
+
+func (h *HeikinAshi) Current() (Candle, bool) {
+	return Candle{}, false
+}
+
+func (h *HeikinAshi) Clone() BarBuilder {
+	clone := *h
+	return &clone
+}
+
+// Renko строит кирпичи Ренко по ценам закрытия. Размер кирпича задается явно
+// либо вычисляется как ATR за первые atrPeriod свечей.
+type Renko struct {
+	boxSize   float64
+	atrPeriod int
+	warmup    []Candle
+	last      Candle // Последний кирпич, O и C задают его границы
+	started   bool
+	volume    float64
+	turnover  float64
+}
+
+func NewRenko(boxSize float64) *Renko {
+	return &Renko{boxSize: boxSize}
+}
+
+func NewATRRenko(atrPeriod int) *Renko {
+	return &Renko{atrPeriod: max(1, atrPeriod)}
+}
+
+// BoxSize возвращает размер кирпича (0, пока ATR не вычислен)
+func (r *Renko) BoxSize() float64 {
+	return r.boxSize
+}
+
+func (r *Renko) Update(c Candle) []Candle {
+	if r.boxSize <= 0 {
+		r.warmup = append(r.warmup, c)
+		if len(r.warmup) <= r.atrPeriod {
+			return nil
+		}
+		r.boxSize = ATR(r.warmup, r.atrPeriod)
+		r.warmup = nil
+		if r.boxSize <= 0 {
+			return nil
+		}
+	}
+	if !r.started {
+		r.last = Candle{Time: c.Time, O: c.C, H: c.C, L: c.C, C: c.C}
+		r.started = true
+		return nil
+	}
+
+	r.volume += c.Volume
+	r.turnover += c.Turnover
+
+	var bricks []Candle
+	for {
+		top := max(r.last.O, r.last.C)
+		bottom := min(r.last.O, r.last.C)
+		var brick Candle
+		switch {
+		case c.C >= top+r.boxSize:
+			brick = Candle{Time: c.Time, O: top, C: top + r.boxSize}
+		case c.C <= bottom-r.boxSize:
+			brick = Candle{Time: c.Time, O: bottom, C: bottom - r.boxSize}
+		default:
+			return bricks
+		}
+		brick.H = max(brick.O, brick.C)
+		brick.L = min(brick.O, brick.C)
+		if len(bricks) == 0 {
+			brick.Volume, brick.Turnover = r.volume, r.turnover
+			r.volume, r.turnover = 0, 0
+		}
+		r.last = brick
+		bricks = append(bricks, brick)
+	}
+}
+
+func (r *Renko) Current() (Candle, bool) {
+	return Candle{}, false
+}
+
+func (r *Renko) Clone() BarBuilder {
+	clone := *r
+	clone.warmup = append([]Candle(nil), r.warmup...)
+	return &clone
+}
+
+// RangeBars строит бары фиксированного диапазона High-Low. Путь цены внутри
+// свечи приближается как O -> L -> H -> C для растущей свечи и O -> H -> L -> C для падающей.
+// Объем свечи относится к бару, формирующемуся после ее обработки.
+type RangeBars struct {
+	size    float64
+	current Candle
+	started bool
+}
+
+func NewRangeBars(size float64) *RangeBars {
+	return &RangeBars{size: size}
+}
+
+func (r *RangeBars) Update(c Candle) []Candle {
+	path := [4]float64{c.O, c.H, c.L, c.C}
+	if c.C >= c.O {
+		path[1], path[2] = c.L, c.H
+	}
+
+	var bars []Candle
+	for _, p := range path {
+		bars = append(bars, r.move(c.Time, p)...)
+	}
+	r.current.Volume += c.Volume
+	r.current.Turnover += c.Turnover
+
+	return bars
+}
+
+// move продвигает цену формирующегося бара до p, закрывая бары при достижении диапазона
+func (r *RangeBars) move(t int64, p float64) []Candle {
+	if !r.started {
+		r.current = Candle{Time: t, O: p, H: p, L: p, C: p}
+		r.started = true
+		return nil
+	}
+
+	var bars []Candle
+	for r.size > 0 {
+		var edge float64
+		switch {
+		case p > r.current.L+r.size:
+			edge = r.current.L + r.size
+			r.current.H = edge
+		case p < r.current.H-r.size:
+			edge = r.current.H - r.size
+			r.current.L = edge
+		default:
+			r.current.H = max(r.current.H, p)
+			r.current.L = min(r.current.L, p)
+			r.current.C = p
+			return bars
+		}
+		r.current.C = edge
+		bars = append(bars, r.current)
+		r.current = Candle{Time: t, O: edge, H: edge, L: edge, C: edge}
+	}
+	return bars
+}
+
+func (r *RangeBars) Current() (Candle, bool) {
+	return r.current, r.started
+}
+
+func (r *RangeBars) Clone() BarBuilder {
+	clone := *r
+	return &clone
+}
+
+// ThresholdBars объединяет свечи в бар, пока накопленный объем
+// или оборот не достигнет порога
+type ThresholdBars struct {
+	threshold float64
+	value     func(c *Candle) float64
+	current   Candle
+	started   bool
+}
+
+func NewVolumeBars(threshold float64) *ThresholdBars {
+	return &ThresholdBars{
+		threshold: threshold,
+		value:     func(c *Candle) float64 { return c.Volume },
+	}
+}
+
+func NewTurnoverBars(threshold float64) *ThresholdBars {
+	return &ThresholdBars{
+		threshold: threshold,
+		value:     func(c *Candle) float64 { return c.Turnover },
+	}
+}
+
+func (t *ThresholdBars) Update(c Candle) []Candle {
+	if t.started {
+		t.current = mergeCandles(t.current, c)
+	} else {
+		t.current, t.started = c, true
+	}
+	if t.value(&t.current) < t.threshold {
+		return nil
+	}
+
+	bar := t.current
+	t.current, t.started = Candle{}, false
+	return []Candle{bar}
+}
+
+func (t *ThresholdBars) Current() (Candle, bool) {
+	return t.current, t.started
+}
+
+func (t *ThresholdBars) Clone() BarBuilder {
+	clone := *t
+	return &clone
+}
@@ -0,0 +1,41 @@
+package credentials
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// Prompt выводит приглашение в stderr и читает строку из stdin.
+// Если stdin - терминал, ввод secret не отображается.
+func Prompt(prompt string, secret bool) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	fd := int(os.Stdin.Fd())
+	if secret && term.IsTerminal(fd) {
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ReadPassphrase возвращает парольную фразу хранилища из переменной окружения
+// CREDENTIALS_PASSPHRASE или запрашивает ее
+func ReadPassphrase() (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	return Prompt("credentials store passphrase: ", true)
+}
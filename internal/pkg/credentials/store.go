@@ -0,0 +1,320 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	DefaultStorePath = "./credentials.enc"
+	PassphraseEnv    = "CREDENTIALS_PASSPHRASE" // Переменная окружения с парольной фразой хранилища
+
+	storeVersion = 1
+	keyLen       = 32 // AES-256
+	saltLen      = 16
+)
+
+// Параметры scrypt по умолчанию (рекомендация для интерактивного входа).
+// Параметры сохраняются в файле, поэтому их изменение не ломает существующие хранилища.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrInvalidPassphrase возвращается, если файл не расшифровывается парольной фразой
+	ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted credentials store")
+	// ErrEmptyPassphrase возвращается при пустой парольной фразе
+	ErrEmptyPassphrase = errors.New("passphrase must not be empty")
+)
+
+// Credential - API-ключи аккаунта биржи. При выводе в логи, fmt и JSON секреты скрываются.
+type Credential struct {
+	Name       string // Имя записи
+	Exchange   string // Биржа: bybit, binance, okx
+	APIKey     string // Публичный API-ключ
+	APISecret  string // Секретный ключ
+	Passphrase string // Парольная фраза API-ключа (okx)
+}
+
+// Redact скрывает секрет, оставляя первые 4 символа достаточно длинного значения
+func Redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) < 12 {
+		return "****"
+	}
+	return s[:4] + "****"
+}
+
+// redacted возвращает копию записи со скрытыми секретами
+func (c Credential) redacted() map[string]string {
+	return map[string]string{
+		"name":       c.Name,
+		"exchange":   c.Exchange,
+		"apiKey":     Redact(c.APIKey),
+		"apiSecret":  Redact(c.APISecret),
+		"passphrase": Redact(c.Passphrase),
+	}
+}
+
+func (c Credential) String() string {
+	return fmt.Sprintf("%s (%s, key %s)", c.Name, c.Exchange, Redact(c.APIKey))
+}
+
+func (c Credential) GoString() string {
+	return fmt.Sprintf("credentials.Credential{Name:%q, Exchange:%q, APIKey:%q}", c.Name, c.Exchange, Redact(c.APIKey))
+}
+
+func (c Credential) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.redacted())
+}
+
+func (c Credential) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", c.Name),
+		slog.String("exchange", c.Exchange),
+		slog.String("apiKey", Redact(c.APIKey)),
+	)
+}
+
+// record - представление записи внутри зашифрованного содержимого
+type record struct {
+	Exchange   string `json:"exchange"`
+	APIKey     string `json:"apiKey"`
+	APISecret  string `json:"apiSecret"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// storeFile - формат файла хранилища
+type storeFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Store - хранилище API-ключей в файле, зашифрованном AES-256-GCM ключом,
+// полученным из парольной фразы через scrypt
+type Store struct {
+	path       string
+	passphrase []byte
+	records    map[string]record
+	isNew      bool // Файл хранилища еще не создан
+	mu         sync.RWMutex
+}
+
+// Open открывает хранилище по пути path. Если файла нет, возвращается пустое хранилище,
+// которое будет создано при первом сохранении.
+func Open(path, passphrase string) (*Store, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	if path == "" {
+		path = DefaultStorePath
+	}
+	s := &Store{
+		path:       path,
+		passphrase: []byte(passphrase),
+		records:    make(map[string]record),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.isNew = true
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid credentials store format: %w", err)
+	}
+	if f.Version != storeVersion || f.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported credentials store version %d (kdf %q)", f.Version, f.KDF)
+	}
+	aead, err := newAEAD(s.passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	if err := json.Unmarshal(plaintext, &s.records); err != nil {
+		return nil, fmt.Errorf("invalid credentials store content: %w", err)
+	}
+
+	return s, nil
+}
+
+// newAEAD создает AES-GCM шифр с ключом, полученным из парольной фразы
+func newAEAD(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Path возвращает путь к файлу хранилища
+func (s *Store) Path() string {
+	return s.path
+}
+
+// IsNew сообщает, что файл хранилища еще не создан и парольная фраза не проверена
+func (s *Store) IsNew() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.isNew
+}
+
+// Get возвращает запись по имени
+func (s *Store) Get(name string) (Credential, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[name]
+	if !ok {
+		return Credential{}, false
+	}
+	return Credential{
+		Name:       name,
+		Exchange:   r.Exchange,
+		APIKey:     r.APIKey,
+		APISecret:  r.APISecret,
+		Passphrase: r.Passphrase,
+	}, true
+}
+
+// List возвращает записи хранилища, отсортированные по имени
+func (s *Store) List() []Credential {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.records))
+	for name := range s.records {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	slices.Sort(names)
+
+	list := make([]Credential, 0, len(names))
+	for _, name := range names {
+		if c, ok := s.Get(name); ok {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Put добавляет или заменяет запись. Изменения записываются в файл методом Save.
+func (s *Store) Put(c Credential) error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("credential name must not be empty")
+	}
+	if c.APIKey == "" || c.APISecret == "" {
+		return errors.New("api key and secret must not be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[c.Name] = record{
+		Exchange:   c.Exchange,
+		APIKey:     c.APIKey,
+		APISecret:  c.APISecret,
+		Passphrase: c.Passphrase,
+	}
+	return nil
+}
+
+// Remove удаляет запись и сообщает, существовала ли она
+func (s *Store) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.records[name]
+	delete(s.records, name)
+	return ok
+}
+
+// Save шифрует записи с новыми солью и nonce и атомарно записывает файл с правами 0600
+func (s *Store) Save() error {
+	s.mu.RLock()
+	plaintext, err := json.Marshal(s.records)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	f := storeFile{
+		Version: storeVersion,
+		KDF:     "scrypt",
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saltLen),
+	}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+	aead, err := newAEAD(s.passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plaintext, nil)
+	data, err := json.MarshalIndent(&f, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".credentials-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.isNew = false
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,216 @@
+package tick
+
+import (
+	"context"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+// DeltaBar - объемы сделок покупателей и продавцов за бар
+type DeltaBar struct {
+	Time       int64   `json:"time"`       // Время открытия бара (мс)
+	BuyVolume  float64 `json:"buyVolume"`  // Объем сделок с покупателем-агрессором
+	SellVolume float64 `json:"sellVolume"` // Объем сделок с продавцом-агрессором
+	Trades     int     `json:"trades"`     // Количество сделок
+}
+
+// Delta возвращает разницу объемов покупок и продаж
+func (b DeltaBar) Delta() float64 {
+	return b.BuyVolume - b.SellVolume
+}
+
+// VolumeDelta накапливает дельту объема по барам интервала свечей,
+// время бара совпадает со временем открытия свечи
+type VolumeDelta struct {
+	interval cdl.Interval
+	current  DeltaBar
+	started  bool
+}
+
+func NewVolumeDelta(interval cdl.Interval) *VolumeDelta {
+	return &VolumeDelta{interval: interval}
+}
+
+// Add учитывает сделку и возвращает завершенный бар, если сделка открыла новый
+func (d *VolumeDelta) Add(t *Tick) (DeltaBar, bool) {
+	var completed DeltaBar
+	var ok bool
+
+	open := d.interval.OpenTime(t.Time)
+	if !d.started || open != d.current.Time {
+		completed, ok = d.current, d.started
+		d.current = DeltaBar{Time: open}
+		d.started = true
+	}
+	if t.Side == Buy {
+		d.current.BuyVolume += t.Size
+	} else {
+		d.current.SellVolume += t.Size
+	}
+	d.current.Trades++
+
+	return completed, ok
+}
+
+// Current возвращает формирующийся бар
+func (d *VolumeDelta) Current() (DeltaBar, bool) {
+	return d.current, d.started
+}
+
+// LargeTradeDetector выделяет крупные сделки по абсолютному порогу стоимости
+// и по отношению к средней стоимости последних сделок
+type LargeTradeDetector struct {
+	minNotional float64
+	factor      float64
+	window      []float64
+	next        int
+	filled      bool
+	sum         float64
+}
+
+// NewLargeTradeDetector создает детектор: сделка крупная, если ее стоимость не меньше
+// minNotional и, при factor > 0, больше средней стоимости window предыдущих сделок в factor раз
+func NewLargeTradeDetector(minNotional, factor float64, window int) *LargeTradeDetector {
+	return &LargeTradeDetector{
+		minNotional: minNotional,
+		factor:      factor,
+		window:      make([]float64, max(1, window)),
+	}
+}
+
+// Check учитывает сделку и сообщает, является ли она крупной
+func (d *LargeTradeDetector) Check(t *Tick) bool {
+	notional := t.Notional()
+
+	large := notional >= d.minNotional
+	if large && d.factor > 0 {
+		n := len(d.window)
+		if !d.filled {
+			n = d.next
+		}
+		large = n > 0 && notional > d.factor*d.sum/float64(n)
+	}
+
+	d.sum += notional - d.window[d.next]
+	d.window[d.next] = notional
+	d.next++
+	if d.next == len(d.window) {
+		d.next, d.filled = 0, true
+	}
+
+	return large
+}
+
+// flushGrace - запас на расхождение часов биржи и локальных часов при завершении свечи по времени
+const flushGrace = 250
+
+// CandleBuilder строит свечи произвольного, в том числе меньше минуты, периода из сделок
+type CandleBuilder struct {
+	period  int64
+	current cdl.Candle
+	started bool
+	flushed int64 // Время открытия последней свечи, завершенной по времени
+}
+
+func NewCandleBuilder(period time.Duration) *CandleBuilder {
+	return &CandleBuilder{period: max(1, period.Milliseconds())}
+}
+
+// Add учитывает сделку и возвращает завершенную свечу, если сделка открыла новую
+func (b *CandleBuilder) Add(t *Tick) (cdl.Candle, bool) {
+	open := t.Time - t.Time%b.period
+	if !b.started && b.flushed != 0 && open <= b.flushed {
+		// Запоздавшая сделка уже завершенной свечи
+		return cdl.Candle{}, false
+	}
+	if b.started && open == b.current.Time {
+		b.current.H = max(b.current.H, t.Price)
+		b.current.L = min(b.current.L, t.Price)
+		b.current.C = t.Price
+		b.current.Volume += t.Size
+		b.current.Turnover += t.Notional()
+		return cdl.Candle{}, false
+	}
+
+	completed, ok := b.current, b.started
+	b.current = cdl.Candle{
+		Time:     open,
+		O:        t.Price,
+		H:        t.Price,
+		L:        t.Price,
+		C:        t.Price,
+		Volume:   t.Size,
+		Turnover: t.Notional(),
+	}
+	b.started = true
+
+	return completed, ok
+}
+
+// Flush завершает текущую свечу, если к моменту now (мс) ее период истек.
+// Более поздние сделки того же периода игнорируются.
+func (b *CandleBuilder) Flush(now int64) (cdl.Candle, bool) {
+	if !b.started || now < b.current.Time+b.period+flushGrace {
+		return cdl.Candle{}, false
+	}
+	b.started = false
+	b.flushed = b.current.Time
+	return b.current, true
+}
+
+// Current возвращает формирующуюся свечу
+func (b *CandleBuilder) Current() (cdl.Candle, bool) {
+	return b.current, b.started
+}
+
+// CandleStream строит поток свечей периода period из потока сделок. Каждая сделка
+// дает неподтвержденное обновление, свеча подтверждается по истечении периода.
+// Interval в данных потока равен 0, так как период может быть меньше минуты.
+func CandleStream(ctx context.Context, ticks <-chan *Tick, period time.Duration) <-chan *cdl.CandleStreamData {
+	out := make(chan *cdl.CandleStreamData)
+	b := NewCandleBuilder(period)
+
+	go func() {
+		defer close(out)
+
+		send := func(c cdl.Candle, confirm bool) bool {
+			select {
+			case out <- &cdl.CandleStreamData{Candle: c, Confirm: confirm}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Истечение периода без новых сделок проверяется по таймеру
+		ticker := time.NewTicker(max(50*time.Millisecond, min(period/4, time.Second)))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if c, ok := b.Flush(now.UnixMilli()); ok && !send(c, true) {
+					return
+				}
+			case t, ok := <-ticks:
+				if !ok {
+					return
+				}
+				if t == nil {
+					continue
+				}
+				if c, ok := b.Add(t); ok && !send(c, true) {
+					return
+				}
+				c, _ := b.Current()
+				if !send(c, false) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
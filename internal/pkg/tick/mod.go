@@ -0,0 +1,110 @@
+package tick
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Side - сторона агрессора сделки
+type Side int8
+
+const (
+	Buy  Side = 1  // Покупатель - агрессор
+	Sell Side = -1 // Продавец - агрессор
+)
+
+// Tick - публичная сделка
+type Tick struct {
+	ID         string  `json:"id"`         // Идентификатор сделки
+	Symbol     string  `json:"symbol"`     // Название торговой пары
+	Time       int64   `json:"time"`       // Время сделки (мс)
+	Side       Side    `json:"side"`       // Сторона агрессора
+	Price      float64 `json:"price"`      // Цена сделки
+	Size       float64 `json:"size"`       // Объем сделки
+	BlockTrade bool    `json:"blockTrade"` // Блочная сделка
+}
+
+// Notional возвращает стоимость сделки
+func (t *Tick) Notional() float64 {
+	return t.Price * t.Size
+}
+
+// TickProvider определяет интерфейс поставщика потока публичных сделок
+type TickProvider interface {
+	TradeStream(ctx context.Context, symbol string) (<-chan *Tick, error)
+}
+
+// subscriber содержит каналы подписчика сделок
+type subscriber struct {
+	ch   chan<- *Tick
+	done <-chan struct{}
+}
+
+// Sync раздает поток сделок инструмента подписчикам
+type Sync struct {
+	Symbol      string
+	subscribers map[string]subscriber
+	mu          sync.RWMutex
+}
+
+// Start подключается к потоку сделок поставщика и рассылает их подписчикам до завершения контекста
+func Start(ctx context.Context, provider TickProvider, symbol string) (*Sync, error) {
+	stream, err := provider.TradeStream(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sync{
+		Symbol:      symbol,
+		subscribers: make(map[string]subscriber),
+	}
+	go func() {
+		defer s.close()
+		for t := range stream {
+			if t != nil {
+				s.broadcast(t)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Subscribe добавляет подписчика. Закрытие возвращенного канала отменяет подписку,
+// после чего ch будет закрыт. Сделки для занятого подписчика отбрасываются.
+func (s *Sync) Subscribe(ch chan<- *Tick) chan<- struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	done := make(chan struct{}, 1)
+	s.subscribers[uuid.NewString()] = subscriber{ch: ch, done: done}
+
+	return done
+}
+
+func (s *Sync) broadcast(t *Tick) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, sub := range s.subscribers {
+		select {
+		case <-sub.done:
+			close(sub.ch)
+			delete(s.subscribers, key)
+		case sub.ch <- t:
+		default:
+		}
+	}
+}
+
+func (s *Sync) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, sub := range s.subscribers {
+		close(sub.ch)
+		delete(s.subscribers, key)
+	}
+}
@@ -0,0 +1,120 @@
+package ticker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Ticker - снимок рыночных данных инструмента
+type Ticker struct {
+	Symbol            string  `json:"symbol"`            // Название торговой пары
+	Time              int64   `json:"time"`              // Время снимка (мс)
+	LastPrice         float64 `json:"lastPrice"`         // Цена последней сделки
+	MarkPrice         float64 `json:"markPrice"`         // Маркировочная цена
+	IndexPrice        float64 `json:"indexPrice"`        // Индексная цена
+	BidPrice          float64 `json:"bidPrice"`          // Лучшая цена покупки
+	BidSize           float64 `json:"bidSize"`           // Объем лучшей цены покупки
+	AskPrice          float64 `json:"askPrice"`          // Лучшая цена продажи
+	AskSize           float64 `json:"askSize"`           // Объем лучшей цены продажи
+	OpenInterest      float64 `json:"openInterest"`      // Открытый интерес
+	OpenInterestValue float64 `json:"openInterestValue"` // Стоимость открытого интереса
+	FundingRate       float64 `json:"fundingRate"`       // Прогнозная ставка ближайшего фандинга
+	NextFundingTime   int64   `json:"nextFundingTime"`   // Время ближайшего фандинга (мс)
+	Price24hPcnt      float64 `json:"price24hPcnt"`      // Изменение цены за 24 часа (доля)
+	Volume24h         float64 `json:"volume24h"`         // Объем за 24 часа
+	Turnover24h       float64 `json:"turnover24h"`       // Оборот за 24 часа
+}
+
+// TickerProvider определяет интерфейс поставщика потока тикеров.
+// Каждое значение потока - полный снимок с учетом предыдущих изменений.
+type TickerProvider interface {
+	TickerStream(ctx context.Context, symbol string) (<-chan *Ticker, error)
+}
+
+// subscriber содержит каналы подписчика тикеров
+type subscriber struct {
+	ch   chan<- *Ticker
+	done <-chan struct{}
+}
+
+// Sync хранит последний тикер инструмента и раздает обновления подписчикам
+type Sync struct {
+	Symbol      string
+	last        *Ticker
+	subscribers map[string]subscriber
+	mu          sync.RWMutex
+}
+
+// Start подключается к потоку тикеров поставщика до завершения контекста
+func Start(ctx context.Context, provider TickerProvider, symbol string) (*Sync, error) {
+	stream, err := provider.TickerStream(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sync{
+		Symbol:      symbol,
+		subscribers: make(map[string]subscriber),
+	}
+	go func() {
+		defer s.close()
+		for t := range stream {
+			if t != nil {
+				s.broadcast(t)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Last возвращает последний полученный тикер
+func (s *Sync) Last() (Ticker, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.last == nil {
+		return Ticker{}, false
+	}
+	return *s.last, true
+}
+
+// Subscribe добавляет подписчика. Закрытие возвращенного канала отменяет подписку,
+// после чего ch будет закрыт. Обновления для занятого подписчика отбрасываются.
+func (s *Sync) Subscribe(ch chan<- *Ticker) chan<- struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	done := make(chan struct{}, 1)
+	s.subscribers[uuid.NewString()] = subscriber{ch: ch, done: done}
+
+	return done
+}
+
+func (s *Sync) broadcast(t *Ticker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last = t
+	for key, sub := range s.subscribers {
+		select {
+		case <-sub.done:
+			close(sub.ch)
+			delete(s.subscribers, key)
+		case sub.ch <- t:
+		default:
+		}
+	}
+}
+
+func (s *Sync) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, sub := range s.subscribers {
+		close(sub.ch)
+		delete(s.subscribers, key)
+	}
+}
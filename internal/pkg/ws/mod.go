@@ -2,48 +2,125 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// ErrNotConnected возвращается при отправке сообщения во время переподключения.
+var ErrNotConnected = errors.New("websocket is not connected")
+
 // connection - WebSocket соединение с поддержкой переподключения.
 type connection struct {
-	conn         *websocket.Conn
-	dialer       websocket.Dialer
-	header       http.Header
-	outChan      chan []byte
-	reconnect    chan bool
-	ctx          context.Context
-	wg           sync.WaitGroup
-	writeWait    time.Duration
-	pongWait     time.Duration
-	pingInterval time.Duration
-	handshake    []byte
+	conn              *websocket.Conn
+	dialer            websocket.Dialer
+	header            http.Header
+	outChan           chan []byte
+	sendChan          chan []byte
+	ctx               context.Context
+	wg                sync.WaitGroup
+	session           chan struct{}
+	connected         atomic.Bool
+	writeWait         time.Duration
+	pongWait          time.Duration
+	pingInterval      time.Duration
+	handshake         []byte
+	handshakeFunc     func() [][]byte
+	heartbeat         []byte
+	heartbeatInterval time.Duration
+	policy            ReconnectPolicy
+	state             State
+	connectedAt       time.Time
+	stateSubs         []stateSub
+	stateMu           sync.Mutex
+	closed            bool
+	reconnects        atomic.Uint64
+	messagesIn        atomic.Uint64
+	messagesOut       atomic.Uint64
+	rateIn            atomic.Uint64 // math.Float64bits частоты входящих сообщений
+	latency           atomic.Int64
+	requestID         RequestIDFunc
+	requestTimeout    time.Duration
+	pending           map[string]chan []byte
+	pendingMu         sync.Mutex
 }
 
 // Connect создает новое WebSocket соединение.
 func Connect(url string, ctx context.Context, opts ...Option) (<-chan []byte, error) {
+	c, err := Dial(url, ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Messages(), nil
+}
+
+// Conn - двунаправленное WebSocket соединение с переподключением.
+type Conn struct {
+	c *connection
+}
+
+// Dial создает новое WebSocket соединение с возможностью отправки сообщений.
+func Dial(url string, ctx context.Context, opts ...Option) (*Conn, error) {
 	c := &connection{
-		outChan:   make(chan []byte),
-		ctx:       ctx,
-		header:    make(http.Header),
-		reconnect: make(chan bool),
+		outChan:  make(chan []byte),
+		sendChan: make(chan []byte, 16),
+		ctx:      ctx,
+		header:   make(http.Header),
 		dialer: websocket.Dialer{
 			HandshakeTimeout: 10 * time.Second,
 		},
-		writeWait:    15 * time.Second,
-		pongWait:     30 * time.Second,
-		pingInterval: (30 * time.Second * 9) / 10,
+		writeWait:      15 * time.Second,
+		pongWait:       30 * time.Second,
+		pingInterval:   (30 * time.Second * 9) / 10,
+		policy:         DefaultReconnectPolicy(),
+		requestID:      JSONRequestID,
+		requestTimeout: 10 * time.Second,
+		pending:        make(map[string]chan []byte),
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	return c.connect(url)
+	if err := c.connect(url, 0); err != nil {
+		return nil, err
+	}
+	return &Conn{c: c}, nil
+}
+
+// Messages возвращает канал входящих сообщений.
+func (c *Conn) Messages() <-chan []byte {
+	return c.c.outChan
+}
+
+// Connected сообщает, установлено ли соединение.
+func (c *Conn) Connected() bool {
+	return c.c.connected.Load()
+}
+
+// Send ставит текстовое сообщение в очередь отправки. Сообщения отправляются в порядке вызовов,
+// сообщения, не отправленные до разрыва соединения, отбрасываются.
+func (c *Conn) Send(data []byte) error {
+	if err := c.c.ctx.Err(); err != nil {
+		return err
+	}
+	if !c.c.connected.Load() {
+		return ErrNotConnected
+	}
+	select {
+	case c.c.sendChan <- data:
+		return nil
+	case <-c.c.ctx.Done():
+		return c.c.ctx.Err()
+	case <-time.After(c.c.writeWait):
+		return fmt.Errorf("websocket send timeout")
+	}
 }
 
 // Option функция настройки соединения.
@@ -54,6 +131,28 @@ func WithHandshake(h []byte) Option {
 	return func(c *connection) { c.handshake = h }
 }
 
+// WithHandshakeFunc устанавливает функцию, формирующую сообщения рукопожатия
+// при каждом подключении, например, для повторной подписки после переподключения.
+func WithHandshakeFunc(f func() [][]byte) Option {
+	return func(c *connection) { c.handshakeFunc = f }
+}
+
+// WithHeartbeat включает отправку текстового сообщения msg с интервалом d
+// для протоколов с пингом на уровне приложения.
+func WithHeartbeat(msg []byte, d time.Duration) Option {
+	return func(c *connection) {
+		if d > 0 {
+			c.heartbeat = msg
+			c.heartbeatInterval = d
+		}
+	}
+}
+
+// WithReconnectPolicy устанавливает политику переподключения.
+func WithReconnectPolicy(p ReconnectPolicy) Option {
+	return func(c *connection) { c.policy = p }
+}
+
 // WithHeader добавляет HTTP заголовки.
 func WithHeader(h http.Header) Option {
 	return func(c *connection) { c.header = h }
@@ -74,26 +173,51 @@ func WithPongTimeout(d time.Duration) Option {
 	}
 }
 
-// connect устанавливает соединение и возвращает канал для чтения.
-func (c *connection) connect(url string) (<-chan []byte, error) {
+// connect устанавливает соединение. attempt - номер попытки переподключения (0 - первое подключение).
+func (c *connection) connect(url string, attempt int) error {
+	c.emit(Connecting, attempt, nil)
+
 	conn, _, err := c.dialer.Dial(url, c.header)
 	if err != nil {
-		return nil, fmt.Errorf("websocket connection error: %w", err)
+		err = fmt.Errorf("websocket connection error: %w", err)
+		c.emit(Disconnected, attempt, err)
+		return err
 	}
 	c.conn = conn
 
+	handshake := make([][]byte, 0, 1)
 	if len(c.handshake) > 0 {
-		if err := c.writeMessage(websocket.TextMessage, c.handshake); err != nil {
+		handshake = append(handshake, c.handshake)
+	}
+	var resubscribe [][]byte
+	if c.handshakeFunc != nil {
+		resubscribe = c.handshakeFunc()
+		handshake = append(handshake, resubscribe...)
+	}
+	for _, h := range handshake {
+		if err := c.writeMessage(websocket.TextMessage, h); err != nil {
 			conn.Close()
-			return nil, fmt.Errorf("websocket sending handshake error: %w", err)
+			err = fmt.Errorf("websocket sending handshake error: %w", err)
+			c.emit(Disconnected, attempt, err)
+			return err
 		}
 	}
+	c.session = make(chan struct{})
+	c.connected.Store(true)
+	if attempt > 0 {
+		c.reconnects.Add(1)
+	}
+	c.emit(Connected, attempt, nil)
+	if attempt > 0 && len(resubscribe) > 0 {
+		c.emit(Resubscribed, attempt, nil)
+	}
 	go c.runPumps(url)
 
-	return c.outChan, nil
+	return nil
 }
 
-// runPumps запускает обработку входящих/исходящих сообщений.
+// runPumps запускает обработку входящих/исходящих сообщений
+// и переподключается согласно политике после разрыва соединения.
 func (c *connection) runPumps(url string) {
 	c.wg.Add(2)
 	go c.readPump()
@@ -101,30 +225,64 @@ func (c *connection) runPumps(url string) {
 	c.wg.Wait()
 
 	c.conn.Close()
+	c.dropQueued()
+	if err := c.ctx.Err(); err != nil {
+		c.terminate(err)
+		return
+	}
+	c.emit(Disconnected, 0, nil)
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-	for {
+	for attempt := 1; ; attempt++ {
+		if c.policy.MaxAttempts > 0 && attempt > c.policy.MaxAttempts {
+			c.terminate(ErrReconnectFailed)
+			return
+		}
+		timer := time.NewTimer(c.policy.Delay(attempt))
 		select {
 		case <-c.ctx.Done():
-			close(c.outChan)
-			close(c.reconnect)
+			timer.Stop()
+			c.terminate(c.ctx.Err())
 			return
-		case <-ticker.C:
-			if _, err := c.connect(url); err == nil {
+		case <-timer.C:
+			if err := c.connect(url, attempt); err == nil {
 				return
 			}
 		}
 	}
 }
 
+// dropQueued отбрасывает сообщения, поставленные в очередь до разрыва соединения,
+// чтобы они не были отправлены в новом соединении
+func (c *connection) dropQueued() {
+	for {
+		select {
+		case <-c.sendChan:
+		default:
+			return
+		}
+	}
+}
+
+// terminate окончательно завершает соединение
+func (c *connection) terminate(err error) {
+	c.emit(Disconnected, 0, err)
+	c.closeStateSubs()
+	close(c.outChan)
+}
+
 // readPump обрабатывает входящие сообщения.
 func (c *connection) readPump() {
 	defer c.signalReconnect()
+	defer close(c.session)
+	defer c.failPending()
 
 	c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
-	c.conn.SetPongHandler(func(string) error {
+	c.conn.SetPongHandler(func(appData string) error {
 		c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
+		// Ping содержит время отправки
+		if sent, err := strconv.ParseInt(appData, 10, 64); err == nil {
+			c.latency.Store(time.Now().UnixNano() - sent)
+		}
 		return nil
 	})
 	for {
@@ -132,6 +290,10 @@ func (c *connection) readPump() {
 		if err != nil {
 			return
 		}
+		c.messagesIn.Add(1)
+		if c.resolve(msg) {
+			continue
+		}
 		select {
 		case <-c.ctx.Done():
 			return
@@ -140,35 +302,65 @@ func (c *connection) readPump() {
 	}
 }
 
-// writePump отправляет ping-сообщения.
+// writePump отправляет ping-сообщения и сообщения из очереди отправки.
 func (c *connection) writePump() {
 	defer c.signalReconnect()
+	// Закрытие соединения прерывает чтение в readPump
+	defer c.conn.Close()
 
 	ticker := time.NewTicker(c.pingInterval)
 	defer ticker.Stop()
+	lastIn, lastTime := c.messagesIn.Load(), time.Now()
+
+	var heartbeat <-chan time.Time
+	if c.heartbeatInterval > 0 {
+		heartbeatTicker := time.NewTicker(c.heartbeatInterval)
+		defer heartbeatTicker.Stop()
+		heartbeat = heartbeatTicker.C
+	}
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
-		case <-ticker.C:
-			if err := c.writeMessage(websocket.PingMessage, nil); err != nil {
+		case <-c.session:
+			return
+		case now := <-ticker.C:
+			in := c.messagesIn.Load()
+			rate := float64(in-lastIn) / now.Sub(lastTime).Seconds()
+			c.rateIn.Store(math.Float64bits(rate))
+			lastIn, lastTime = in, now
+
+			ping := strconv.AppendInt(nil, now.UnixNano(), 10)
+			if err := c.writeMessage(websocket.PingMessage, ping); err != nil {
+				return
+			}
+		case <-heartbeat:
+			if err := c.writeMessage(websocket.TextMessage, c.heartbeat); err != nil {
+				return
+			}
+		case data := <-c.sendChan:
+			if err := c.writeMessage(websocket.TextMessage, data); err != nil {
 				return
 			}
 		}
 	}
 }
 
-// signalReconnect уведомляет о переподключении.
+// signalReconnect отмечает разрыв соединения для переподключения.
 func (c *connection) signalReconnect() {
+	c.connected.Store(false)
 	c.wg.Done()
-	select {
-	case c.reconnect <- true:
-	default:
-	}
 }
 
 // writeMessage отправляет сообщение с таймаутом.
 func (c *connection) writeMessage(msgType int, data []byte) error {
 	c.conn.SetWriteDeadline(time.Now().Add(c.writeWait))
-	return c.conn.WriteMessage(msgType, data)
+	if err := c.conn.WriteMessage(msgType, data); err != nil {
+		return err
+	}
+	if msgType == websocket.TextMessage {
+		c.messagesOut.Add(1)
+	}
+	return nil
 }
@@ -0,0 +1,125 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRequestTimeout возвращается, если ответ на запрос не получен за время ожидания.
+	ErrRequestTimeout = errors.New("websocket request timeout")
+	// ErrConnectionLost возвращается, если соединение разорвано до получения ответа.
+	ErrConnectionLost = errors.New("websocket connection lost before response")
+)
+
+// RequestIDFunc извлекает идентификатор запроса из входящего сообщения.
+type RequestIDFunc func(msg []byte) (string, bool)
+
+// JSONRequestID извлекает идентификатор из полей "req_id" или "reqId" JSON сообщения.
+func JSONRequestID(msg []byte) (string, bool) {
+	var m struct {
+		ReqID    string `json:"req_id"`
+		ReqIDAlt string `json:"reqId"`
+	}
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return "", false
+	}
+	if m.ReqID != "" {
+		return m.ReqID, true
+	}
+	return m.ReqIDAlt, m.ReqIDAlt != ""
+}
+
+// WithRequestID устанавливает функцию извлечения идентификатора запроса из ответов.
+func WithRequestID(f RequestIDFunc) Option {
+	return func(c *connection) { c.requestID = f }
+}
+
+// WithRequestTimeout устанавливает время ожидания ответа на запрос.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *connection) {
+		if d > 0 {
+			c.requestTimeout = d
+		}
+	}
+}
+
+// Request отправляет сообщение data с идентификатором id и ожидает ответ с тем же
+// идентификатором. Ответ не попадает в канал Messages. Запросы отправляются в порядке
+// вызовов вместе с сообщениями Send.
+func (c *Conn) Request(ctx context.Context, id string, data []byte) ([]byte, error) {
+	resp := make(chan []byte, 1)
+
+	c.c.pendingMu.Lock()
+	if _, ok := c.c.pending[id]; ok {
+		c.c.pendingMu.Unlock()
+		return nil, fmt.Errorf("duplicate websocket request id: %s", id)
+	}
+	c.c.pending[id] = resp
+	c.c.pendingMu.Unlock()
+
+	defer func() {
+		c.c.pendingMu.Lock()
+		if c.c.pending[id] == resp {
+			delete(c.c.pending, id)
+		}
+		c.c.pendingMu.Unlock()
+	}()
+
+	if err := c.Send(data); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(c.c.requestTimeout)
+	defer timer.Stop()
+
+	select {
+	case msg, ok := <-resp:
+		if !ok {
+			return nil, ErrConnectionLost
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.c.ctx.Done():
+		return nil, c.c.ctx.Err()
+	case <-timer.C:
+		return nil, ErrRequestTimeout
+	}
+}
+
+// resolve передает сообщение ожидающему запросу и сообщает, был ли он найден
+func (c *connection) resolve(msg []byte) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	if len(c.pending) == 0 || c.requestID == nil {
+		return false
+	}
+	id, ok := c.requestID(msg)
+	if !ok {
+		return false
+	}
+	resp, ok := c.pending[id]
+	if !ok {
+		return false
+	}
+	delete(c.pending, id)
+	resp <- msg
+
+	return true
+}
+
+// failPending завершает ожидающие запросы при разрыве соединения
+func (c *connection) failPending() {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	for id, resp := range c.pending {
+		close(resp)
+		delete(c.pending, id)
+	}
+}
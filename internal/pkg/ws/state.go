@@ -0,0 +1,161 @@
+package ws
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrReconnectFailed возвращается, когда исчерпаны попытки переподключения.
+var ErrReconnectFailed = errors.New("websocket reconnect attempts exhausted")
+
+// State - состояние соединения.
+type State string
+
+// Константы состояний соединения
+const (
+	Connecting   State = "connecting"   // Выполняется подключение
+	Connected    State = "connected"    // Соединение установлено
+	Disconnected State = "disconnected" // Соединение разорвано
+	Resubscribed State = "resubscribed" // После переподключения повторно отправлено рукопожатие
+)
+
+// StateEvent - событие изменения состояния соединения.
+type StateEvent struct {
+	State   State     // Новое состояние
+	Time    time.Time // Время события
+	Attempt int       // Номер попытки переподключения (0 - первое подключение)
+	Err     error     // Причина разрыва или неудачной попытки
+}
+
+// ReconnectPolicy - политика переподключения с экспоненциальной задержкой.
+type ReconnectPolicy struct {
+	MinDelay    time.Duration // Задержка перед первой попыткой
+	MaxDelay    time.Duration // Максимальная задержка
+	Multiplier  float64       // Множитель задержки для каждой следующей попытки
+	Jitter      float64       // Доля случайного отклонения задержки в диапазоне [0, 1]
+	MaxAttempts int           // Максимум попыток подряд (0 - без ограничения)
+}
+
+// DefaultReconnectPolicy возвращает политику по умолчанию: от 1 до 30 секунд, без ограничения попыток.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MinDelay:   time.Second,
+		MaxDelay:   30 * time.Second,
+		Multiplier: 2,
+		Jitter:     .2,
+	}
+}
+
+// Delay возвращает задержку перед попыткой attempt (начиная с 1).
+func (p ReconnectPolicy) Delay(attempt int) time.Duration {
+	d := float64(p.MinDelay) * math.Pow(max(1, p.Multiplier), float64(max(0, attempt-1)))
+	if p.MaxDelay > 0 {
+		d = min(d, float64(p.MaxDelay))
+	}
+	if p.Jitter > 0 {
+		d *= 1 + min(1, p.Jitter)*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// Stats - счетчики соединения.
+type Stats struct {
+	State         State         // Текущее состояние
+	ConnectedAt   time.Time     // Время установки текущего соединения
+	Reconnects    uint64        // Количество успешных переподключений
+	MessagesIn    uint64        // Количество полученных сообщений
+	MessagesOut   uint64        // Количество отправленных текстовых сообщений
+	MessageRateIn float64       // Входящих сообщений в секунду за последний интервал ping
+	Latency       time.Duration // Время ответа на последний ping
+}
+
+// stateSub - подписчик событий состояния
+type stateSub struct {
+	ch   chan<- StateEvent
+	done <-chan struct{}
+}
+
+// SubscribeState добавляет подписчика событий состояния соединения. События отправляются
+// без блокировки, поэтому канал должен быть буферизован. Канал закрывается после отписки
+// или окончательного завершения соединения.
+func (c *Conn) SubscribeState(ch chan<- StateEvent) chan<- struct{} {
+	c.c.stateMu.Lock()
+	defer c.c.stateMu.Unlock()
+
+	done := make(chan struct{}, 1)
+	if c.c.closed {
+		close(ch)
+		return done
+	}
+	c.c.stateSubs = append(c.c.stateSubs, stateSub{ch: ch, done: done})
+
+	return done
+}
+
+// State возвращает текущее состояние соединения.
+func (c *Conn) State() State {
+	c.c.stateMu.Lock()
+	defer c.c.stateMu.Unlock()
+
+	return c.c.state
+}
+
+// Stats возвращает счетчики соединения.
+func (c *Conn) Stats() Stats {
+	c.c.stateMu.Lock()
+	state, connectedAt := c.c.state, c.c.connectedAt
+	c.c.stateMu.Unlock()
+
+	return Stats{
+		State:         state,
+		ConnectedAt:   connectedAt,
+		Reconnects:    c.c.reconnects.Load(),
+		MessagesIn:    c.c.messagesIn.Load(),
+		MessagesOut:   c.c.messagesOut.Load(),
+		MessageRateIn: math.Float64frombits(c.c.rateIn.Load()),
+		Latency:       time.Duration(c.c.latency.Load()),
+	}
+}
+
+// emit сохраняет состояние и рассылает событие подписчикам
+func (c *connection) emit(state State, attempt int, err error) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	now := time.Now()
+	c.state = state
+	if state == Connected {
+		c.connectedAt = now
+	}
+	event := StateEvent{State: state, Time: now, Attempt: attempt, Err: err}
+
+	subs := c.stateSubs[:0]
+	for _, sub := range c.stateSubs {
+		select {
+		case <-sub.done:
+			close(sub.ch)
+			continue
+		default:
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+		subs = append(subs, sub)
+	}
+	c.stateSubs = subs
+}
+
+// closeStateSubs закрывает каналы подписчиков после завершения соединения
+func (c *connection) closeStateSubs() {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.closed = true
+	for _, sub := range c.stateSubs {
+		close(sub.ch)
+	}
+	c.stateSubs = nil
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +21,15 @@ type Strategy interface {
 	Stop() bool
 }
 
+// SymbolsProvider - стратегия, сообщающая символы, по которым она торгует.
+// Bot.Stop отменяет ордера по этим символам, даже если бот еще не выставлял по ним ордера.
+type SymbolsProvider interface {
+	Symbols() []string
+}
+
+// stopOrdersTimeout - максимальное время ожидания закрытия ордеров при остановке бота
+const stopOrdersTimeout = 5 * time.Second
+
 type TradingBot struct {
 	ctx              context.Context
 	broker           broker.Broker
@@ -27,6 +38,9 @@ type TradingBot struct {
 	orderRequestChan chan *OrderRequest
 	strategies       map[string]Strategy
 	strategiesMu     sync.Mutex
+	symbols          map[string]struct{} // Символы, по которым выставлялись ордера
+	symbolsMu        sync.Mutex
+	inflight         atomic.Int64 // Число ордеров, обрабатываемых orderRequestHandler
 }
 
 func NewTradingBot(ctx context.Context, broker broker.Broker, logger *slog.Logger) *TradingBot {
@@ -42,6 +56,7 @@ func NewTradingBot(ctx context.Context, broker broker.Broker, logger *slog.Logge
 		logger:           asyncSlog,
 		orderRequestChan: make(chan *OrderRequest),
 		strategies:       make(map[string]Strategy),
+		symbols:          make(map[string]struct{}),
 	}
 
 	go func() {
@@ -62,16 +77,107 @@ func (b *TradingBot) log(level slog.Level, msg string, args ...any) {
 	}
 }
 
+// Stop отменяет активные ордера по символам бота и стратегий, дожидается итоговых
+// исполнений и только затем останавливает стратегии, чтобы отмена не затронула
+// ордера закрытия позиций. Оставшиеся после остановки ордера отменяются.
 func (b *TradingBot) Stop() {
 	b.strategiesMu.Lock()
 	defer b.strategiesMu.Unlock()
 
+	symbols := b.orderSymbols()
+	for _, s := range b.strategies {
+		if p, ok := s.(SymbolsProvider); ok {
+			for _, symbol := range p.Symbols() {
+				if !slices.Contains(symbols, symbol) {
+					symbols = append(symbols, symbol)
+				}
+			}
+		}
+	}
+
+	b.cancelAllOrders(symbols)
+	b.waitOrders(stopOrdersTimeout)
 	for _, s := range b.strategies {
 		s.Stop()
 	}
+	if !b.waitOrders(stopOrdersTimeout) {
+		b.log(slog.LevelError, "orders are still open after strategies stop", "count", b.inflight.Load())
+	}
+	b.cancelAllOrders(symbols)
 
 	b.log(slog.LevelInfo, "trading bot stopped")
+}
 
+// waitOrders ожидает завершения обработки всех ордеров, но не дольше timeout.
+// Проверка начинается через интервал опроса, чтобы обработчик успел принять
+// только что отправленные запросы. Возвращает false по истечении timeout.
+func (b *TradingBot) waitOrders(timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-deadline:
+			return b.inflight.Load() == 0
+		case <-time.After(100 * time.Millisecond):
+			if b.inflight.Load() == 0 {
+				return true
+			}
+		}
+	}
+}
+
+// orderSymbols возвращает символы, по которым бот выставлял ордера
+func (b *TradingBot) orderSymbols() []string {
+	b.symbolsMu.Lock()
+	defer b.symbolsMu.Unlock()
+
+	symbols := make([]string, 0, len(b.symbols))
+	for symbol := range b.symbols {
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// CancelAllOrders отменяет все активные ордера по символам, по которым бот выставлял ордера,
+// одним запросом на символ. Возвращает ID отмененных ордеров.
+func (b *TradingBot) CancelAllOrders() []string {
+	return b.cancelAllOrders(b.orderSymbols())
+}
+
+func (b *TradingBot) cancelAllOrders(symbols []string) []string {
+	var cancelled []string
+	for _, symbol := range symbols {
+		orderIds, err := b.broker.CancelAllOrders(symbol)
+		if err != nil {
+			b.log(
+				slog.LevelError,
+				"failed to cancel all orders",
+				"symbol", symbol,
+				"error", err,
+			)
+			continue
+		}
+		cancelled = append(cancelled, orderIds...)
+	}
+	if len(cancelled) > 0 {
+		b.log(slog.LevelInfo, "open orders cancelled", "orderIds", cancelled)
+	}
+
+	return cancelled
+}
+
+// ListOpenOrders возвращает все активные ордера аккаунта по символу (пустой symbol - по всем символам),
+// включая ордера, выставленные вне бота
+func (b *TradingBot) ListOpenOrders(symbol string) ([]*Order, error) {
+	data, err := b.broker.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var orders []*Order
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
 }
 
 func (b *TradingBot) Resume() error {
@@ -122,36 +228,51 @@ func (b *TradingBot) AddStrategy(s Strategy) (string, error) {
 	return strategyID, nil
 }
 
-func (b *TradingBot) replyOrder(req *OrderRequest) {
+func (b *TradingBot) replyOrder(req *OrderRequest, err error) {
 	if req.Reply == nil {
 		return
 	}
-	select {
-	case req.Reply <- &OrderUpdate{
+	update := &OrderUpdate{
 		LinkId: req.LinkId,
 		Tag:    req.Tag,
 		Order:  req.Order.Clone(),
-	}:
+	}
+	if err != nil {
+		update.Error = err.Error()
+	}
+	select {
+	case req.Reply <- update:
 	case <-time.After(time.Second):
 	}
 }
 
 func (b *TradingBot) orderRequestHandler() {
 	for req := range b.orderRequestChan {
+		b.symbolsMu.Lock()
+		b.symbols[req.Order.Symbol] = struct{}{}
+		b.symbolsMu.Unlock()
+
+		b.inflight.Add(1)
 		go func() {
-			if !b.placeOrderWithRetry(req) {
+			defer b.inflight.Add(-1)
+			if err := b.placeOrderWithRetry(req); err != nil {
+				b.replyOrder(req, err)
 				return
 			}
-			b.replyOrder(req)
+			b.replyOrder(req, nil)
 
 			var err error
 			if !b.waitForOrderClosed(req) {
-				err = fmt.Errorf("waiting time for order closing has expired")
+				if req.Ctx == nil || req.Ctx.Err() == nil {
+					err = fmt.Errorf("waiting time for order closing has expired")
+				}
 				if !b.cancelOrderWithRetry(req) {
 					err = fmt.Errorf("failed to cancel unclosed order")
+				} else {
+					b.refreshOrder(req)
 				}
 			}
-			b.replyOrder(req)
+			b.replyOrder(req, nil)
 
 			logLevel := slog.LevelInfo
 			if err != nil {
@@ -167,7 +288,7 @@ func (b *TradingBot) orderRequestHandler() {
 	}
 }
 
-func (b *TradingBot) placeOrderWithRetry(req *OrderRequest) bool {
+func (b *TradingBot) placeOrderWithRetry(req *OrderRequest) error {
 	if req.Delay > 0 {
 		time.Sleep(req.Delay)
 	}
@@ -182,7 +303,7 @@ func (b *TradingBot) placeOrderWithRetry(req *OrderRequest) bool {
 		if err == nil {
 			req.Order.ID = orderId
 			req.Order.Unlock()
-			return true
+			return nil
 		}
 		req.Order.Unlock()
 
@@ -195,36 +316,48 @@ func (b *TradingBot) placeOrderWithRetry(req *OrderRequest) bool {
 				"error", err,
 				"orderRequest", req.Clone(),
 			)
-			return false
+			return fmt.Errorf("order registration deadline has expired: %w", err)
 		}
 	}
 }
 
 func (b *TradingBot) waitForOrderClosed(req *OrderRequest) bool {
 	timeout := time.After(req.CloseTimeout)
+	var cancel <-chan struct{}
+	if req.Ctx != nil {
+		cancel = req.Ctx.Done()
+	}
 	for {
 		select {
 		case <-time.After(100 * time.Millisecond):
-			data, err := b.broker.GetOrder(req.Order.ID)
-			if err != nil {
-				continue
-			}
-			var updatedOrder Order
-			if err := json.Unmarshal(data, &updatedOrder); err != nil {
-				continue
-			}
-			if updatedOrder.IsClosed {
-				req.Order.Lock()
-				req.Order.Replace(&updatedOrder)
-				req.Order.Unlock()
+			if b.refreshOrder(req) {
 				return true
 			}
+		case <-cancel:
+			return false
 		case <-timeout:
 			return false
 		}
 	}
 }
 
+// refreshOrder обновляет состояние ордера и сообщает, закрыт ли он
+func (b *TradingBot) refreshOrder(req *OrderRequest) bool {
+	data, err := b.broker.GetOrder(req.Order.ID)
+	if err != nil {
+		return false
+	}
+	var updatedOrder Order
+	if err := json.Unmarshal(data, &updatedOrder); err != nil {
+		return false
+	}
+	req.Order.Lock()
+	req.Order.Replace(&updatedOrder)
+	req.Order.Unlock()
+
+	return updatedOrder.IsClosed
+}
+
 func (b *TradingBot) cancelOrderWithRetry(req *OrderRequest) bool {
 	timeout := time.After(5 * time.Minute)
 	for {
@@ -2,40 +2,196 @@ package trading
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 )
 
-const DefaultTradingBotConfigPath = "./config.json"
+const (
+	DefaultTradingBotConfigPath = "./config.json"
+	DefaultStrategyType         = "trend"
+	DefaultAccountName          = "default"
+)
+
+// Поддерживаемые биржи аккаунтов
+const (
+	ExchangeBybit   = "bybit"
+	ExchangeBinance = "binance"
+	ExchangeOKX     = "okx"
+)
+
+// Сети бирж
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkDemo    = "demo"
+)
 
 type StrategyConfig struct {
-	Symbol           string    `json:"symbol"`
-	Interval         string    `json:"interval"`
-	AvailableBalance float64   `json:"availableBalance"`
-	LongRatio        *float64  `json:"longRatio"`
-	MartngaleRatios  []float64 `json:"martngaleRatios"`
-	TrendZoneFilter  *float64  `json:"trendZoneFilter"`
-	LimitOrderOffset *float64  `json:"limitOrderOffset"`
+	Type    string          `json:"type"`              // Имя зарегистрированного типа стратегии
+	Account string          `json:"account,omitempty"` // Имя аккаунта (пусто - первый аккаунт конфигурации)
+	Params  json.RawMessage `json:"params"`            // Параметры стратегии, проверяются по схеме типа
+}
+
+// UnmarshalJSON поддерживает устаревший плоский формат записи без "params":
+// в этом случае весь объект (кроме "type" и "account") считается параметрами стратегии.
+func (c *StrategyConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	c.Type = DefaultStrategyType
+	if raw, ok := fields["type"]; ok {
+		if err := json.Unmarshal(raw, &c.Type); err != nil {
+			return err
+		}
+		delete(fields, "type")
+	}
+	if raw, ok := fields["account"]; ok {
+		if err := json.Unmarshal(raw, &c.Account); err != nil {
+			return err
+		}
+		delete(fields, "account")
+	}
+	if raw, ok := fields["params"]; ok {
+		c.Params = raw
+		return nil
+	}
+
+	params, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	c.Params = params
+
+	return nil
+}
+
+// CredentialsConfig - источник API-ключей аккаунта
+type CredentialsConfig struct {
+	Store string `json:"store,omitempty"` // Имя записи в зашифрованном хранилище ключей (приоритетнее env)
+	Env   string `json:"env,omitempty"`   // Префикс переменных окружения: <ENV>_API_KEY, <ENV>_API_SECRET, <ENV>_API_PASSPHRASE (по умолчанию - имя биржи)
+}
+
+// EnvPrefix возвращает префикс переменных окружения ключей аккаунта биржи exchange
+func (c *CredentialsConfig) EnvPrefix(exchange string) string {
+	if c.Env != "" {
+		return c.Env
+	}
+	return strings.ToUpper(exchange)
+}
+
+// AccountConfig - именованный аккаунт биржи. Для каждого аккаунта создается
+// отдельный торговый бот со своими данными подписок.
+type AccountConfig struct {
+	Name        string            `json:"name"`              // Уникальное имя аккаунта
+	Exchange    string            `json:"exchange"`          // Биржа: bybit, binance, okx
+	Category    string            `json:"category"`          // Категория инструментов брокера (spot, linear, inverse)
+	Network     string            `json:"network,omitempty"` // Сеть: mainnet, testnet, demo (по умолчанию mainnet)
+	BaseURL     string            `json:"baseURL,omitempty"` // Базовый URL API, заменяет URL сети
+	TradeWS     bool              `json:"tradeWS,omitempty"` // Отправка ордеров через WebSocket API брокера
+	Credentials CredentialsConfig `json:"credentials"`       // Источник API-ключей
 }
 
 type TradingBotConfig struct {
+	Category   string           `json:"category,omitempty"` // Устаревшее: категория аккаунта по умолчанию, если accounts не заданы
+	TradeWS    bool             `json:"tradeWS,omitempty"`  // Устаревшее: WebSocket API аккаунта по умолчанию, если accounts не заданы
+	Accounts   []AccountConfig  `json:"accounts,omitempty"` // Аккаунты бирж
 	Strategies []StrategyConfig `json:"strategies"`
 }
 
+// normalize заполняет значения по умолчанию и проверяет ссылки стратегий на аккаунты.
+// Без accounts создается аккаунт bybit по устаревшим полям category и tradeWS.
+func (c *TradingBotConfig) normalize() error {
+	if len(c.Accounts) == 0 {
+		category := c.Category
+		if category == "" {
+			category = "linear"
+		}
+		c.Accounts = []AccountConfig{{
+			Name:     DefaultAccountName,
+			Exchange: ExchangeBybit,
+			Category: category,
+			TradeWS:  c.TradeWS,
+		}}
+		c.Category = ""
+		c.TradeWS = false
+	}
+
+	names := make(map[string]struct{}, len(c.Accounts))
+	for i := range c.Accounts {
+		a := &c.Accounts[i]
+		if a.Name == "" {
+			return fmt.Errorf("account %d: name is required", i)
+		}
+		if _, ok := names[a.Name]; ok {
+			return fmt.Errorf("account %s: duplicate name", a.Name)
+		}
+		names[a.Name] = struct{}{}
+		switch a.Exchange {
+		case ExchangeBybit, ExchangeBinance, ExchangeOKX:
+		default:
+			return fmt.Errorf("account %s: unsupported exchange %q", a.Name, a.Exchange)
+		}
+		if a.Category == "" {
+			a.Category = "linear"
+		}
+		if a.Network == "" {
+			a.Network = NetworkMainnet
+		}
+		switch a.Network {
+		case NetworkMainnet, NetworkTestnet, NetworkDemo:
+		default:
+			return fmt.Errorf("account %s: unsupported network %q", a.Name, a.Network)
+		}
+	}
+
+	for i := range c.Strategies {
+		s := &c.Strategies[i]
+		if s.Account == "" {
+			s.Account = c.Accounts[0].Name
+		}
+		if _, ok := names[s.Account]; !ok {
+			return fmt.Errorf("strategy %d: unknown account %q", i, s.Account)
+		}
+	}
+
+	return nil
+}
+
+// StrategiesByAccount группирует стратегии по именам аккаунтов
+func (c *TradingBotConfig) StrategiesByAccount() map[string][]StrategyConfig {
+	groups := make(map[string][]StrategyConfig, len(c.Accounts))
+	for _, s := range c.Strategies {
+		groups[s.Account] = append(groups[s.Account], s)
+	}
+	return groups
+}
+
 func DefaultTradingBotConfig() *TradingBotConfig {
-	longRatio := .5
-	trendZoneFilter := .5
-	limitOrderOffset := .01
+	params, _ := json.Marshal(map[string]any{
+		"symbol":           "",
+		"interval":         "M5",
+		"availableBalance": 15,
+		"longRatio":        .5,
+		"martngaleRatios":  []float64{1.1},
+		"trendZoneFilter":  .5,
+		"limitOrderOffset": .01,
+	})
 
 	sc := StrategyConfig{
-		Interval:         "M5",
-		AvailableBalance: 15,
-		LongRatio:        &longRatio,
-		MartngaleRatios:  []float64{1.1},
-		TrendZoneFilter:  &trendZoneFilter,
-		LimitOrderOffset: &limitOrderOffset,
+		Type:   DefaultStrategyType,
+		Params: params,
 	}
 
 	return &TradingBotConfig{
+		Accounts: []AccountConfig{{
+			Name:     DefaultAccountName,
+			Exchange: ExchangeBybit,
+			Category: "linear",
+			Network:  NetworkMainnet,
+		}},
 		Strategies: []StrategyConfig{sc},
 	}
 }
@@ -53,6 +209,9 @@ func LoadTradingBotConfig(path string) (*TradingBotConfig, error) {
 	if err := json.Unmarshal(data, &tradingBotConfig); err != nil {
 		return nil, err
 	}
+	if err := tradingBotConfig.normalize(); err != nil {
+		return nil, err
+	}
 
 	return &tradingBotConfig, nil
 }
@@ -0,0 +1,159 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FundingProvider - необязательное расширение DataProvider для бессрочных контрактов
+type FundingProvider interface {
+	GetFundingInfo(symbol string) ([]byte, error)
+}
+
+// FundingInfo - прогноз ближайшего расчета фандинга
+type FundingInfo struct {
+	Rate            float64 `json:"fundingRate"`     // Прогнозная ставка ближайшего фандинга
+	NextFundingTime int64   `json:"nextFundingTime"` // Время ближайшего фандинга (мс)
+	Interval        int     `json:"fundingInterval"` // Период фандинга в минутах
+	MarkPrice       float64 `json:"markPrice"`       // Маркировочная цена
+}
+
+func (s *SubData) GetFundingInfo(symbol string) (*FundingInfo, error) {
+	fundingProvider, ok := s.dataProvider.(FundingProvider)
+	if !ok {
+		return nil, fmt.Errorf("data provider does not support funding info")
+	}
+	b, err := fundingProvider.GetFundingInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var fundingInfo FundingInfo
+	if err = json.Unmarshal(b, &fundingInfo); err != nil {
+		return nil, err
+	}
+	return &fundingInfo, nil
+}
+
+const fundingGuardRefresh = time.Minute
+
+// FundingGuard помогает стратегии не держать позицию через дорогой расчет фандинга
+// и ведет учет расчетов фандинга в журнале позиции (см. Run).
+type FundingGuard struct {
+	subData *SubData
+	symbol  string
+	ledger  *PositionLedger
+	maxRate float64
+	window  time.Duration
+
+	info      *FundingInfo
+	updatedAt time.Time
+	mu        sync.Mutex
+}
+
+// NewFundingGuard создает защиту от фандинга: позиция считается нежелательной,
+// если до расчета осталось не больше window, а ее удержание обойдется дороже maxRate.
+func NewFundingGuard(subData *SubData, symbol string, ledger *PositionLedger, maxRate float64, window time.Duration) *FundingGuard {
+	return &FundingGuard{
+		subData: subData,
+		symbol:  symbol,
+		ledger:  ledger,
+		maxRate: maxRate,
+		window:  window,
+	}
+}
+
+// Info возвращает прогноз фандинга, обновляя его не чаще раза в минуту
+// и сразу после наступления времени расчета.
+func (g *FundingGuard) Info() (*FundingInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if g.info != nil && now.Sub(g.updatedAt) < fundingGuardRefresh && now.UnixMilli() < g.info.NextFundingTime {
+		return g.info, nil
+	}
+
+	info, err := g.subData.GetFundingInfo(g.symbol)
+	if err != nil {
+		return nil, err
+	}
+	g.info = info
+	g.updatedAt = now
+
+	return info, nil
+}
+
+// Run учитывает расчеты фандинга в журнале позиции до завершения ctx. Каждый расчет
+// учитывается в момент NextFundingTime по количеству позиции на этот момент. Учет является
+// оценкой по прогнозной ставке и маркировочной цене последнего обновления перед расчетом.
+func (g *FundingGuard) Run(ctx context.Context) {
+	if g.ledger == nil {
+		return
+	}
+
+	var pending *FundingInfo // Прогноз ближайшего неучтенного расчета
+	for {
+		if info, err := g.Info(); err == nil && info.NextFundingTime > time.Now().UnixMilli() {
+			pending = info
+		}
+
+		wait := fundingGuardRefresh
+		if pending != nil {
+			wait = min(wait, time.Until(time.UnixMilli(pending.NextFundingTime)))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if pending == nil || time.Now().UnixMilli() < pending.NextFundingTime {
+			continue
+		}
+		if qty := g.ledger.Qty(); qty != 0 {
+			g.ledger.ApplyFunding(
+				NewFundingPayment(pending.NextFundingTime, pending.Rate, qty, pending.MarkPrice),
+			)
+		}
+		pending = nil
+	}
+}
+
+// Cost возвращает ожидаемую долю от стоимости позиции qty, уплачиваемую
+// в ближайший расчет (получение < 0).
+func (g *FundingGuard) Cost(qty float64) (float64, error) {
+	info, err := g.Info()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case qty > 0:
+		return info.Rate, nil
+	case qty < 0:
+		return -info.Rate, nil
+	}
+	return 0, nil
+}
+
+// ShouldAvoid сообщает, что позицию qty не следует держать через ближайший расчет.
+// При ошибке получения прогноза возвращает false.
+func (g *FundingGuard) ShouldAvoid(qty float64) bool {
+	if qty == 0 {
+		return false
+	}
+	cost, err := g.Cost(qty)
+	if err != nil || cost <= g.maxRate {
+		return false
+	}
+
+	g.mu.Lock()
+	untilFunding := time.Until(time.UnixMilli(g.info.NextFundingTime))
+	g.mu.Unlock()
+
+	return untilFunding >= 0 && untilFunding <= g.window
+}
@@ -0,0 +1,202 @@
+package trading
+
+import (
+	"math"
+	"sync"
+
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+// Fill - единичное исполнение по инструменту
+type Fill struct {
+	Qty   float64 `json:"qty"`   // Исполненное количество со знаком (продажа < 0)
+	Price float64 `json:"price"` // Цена исполнения
+	Fee   float64 `json:"fee"`   // Комиссия за исполнение
+	Time  int64   `json:"time"`  // Время исполнения (мс)
+}
+
+// Position - снимок состояния позиции в журнале
+type Position struct {
+	Qty         float64 `json:"qty"`         // Количество со знаком (шорт < 0)
+	AvgPrice    float64 `json:"avgPrice"`    // Средневзвешенная цена входа
+	RealizedPnL float64 `json:"realizedPnl"` // Реализованный PnL без учета комиссий и фандинга
+	Fees        float64 `json:"fees"`        // Сумма уплаченных комиссий
+	Funding     float64 `json:"funding"`     // Сумма фандинга (получено > 0, уплачено < 0)
+	Fills       int     `json:"fills"`       // Количество учтенных исполнений
+}
+
+// UnrealizedPnL вычисляет нереализованный PnL по цене markPrice
+func (p Position) UnrealizedPnL(markPrice float64) float64 {
+	if p.Qty == 0 {
+		return 0
+	}
+	return (markPrice - p.AvgPrice) * p.Qty
+}
+
+// NetPnL возвращает реализованный PnL за вычетом комиссий и с учетом фандинга
+func (p Position) NetPnL() float64 {
+	return p.RealizedPnL - p.Fees + p.Funding
+}
+
+// FundingPayment - запись о расчете фандинга по позиции
+type FundingPayment struct {
+	Time   int64   `json:"time"`   // Время расчета фандинга (мс)
+	Rate   float64 `json:"rate"`   // Ставка фандинга
+	Qty    float64 `json:"qty"`    // Количество позиции со знаком на момент расчета
+	Price  float64 `json:"price"`  // Маркировочная цена на момент расчета
+	Amount float64 `json:"amount"` // Сумма платежа (получено > 0, уплачено < 0)
+}
+
+// NewFundingPayment рассчитывает платеж фандинга: при положительной ставке
+// лонг платит шорту, при отрицательной - наоборот.
+func NewFundingPayment(time int64, rate, qty, price float64) FundingPayment {
+	return FundingPayment{
+		Time:   time,
+		Rate:   rate,
+		Qty:    qty,
+		Price:  price,
+		Amount: -qty * price * rate,
+	}
+}
+
+type orderExec struct {
+	qty   float64
+	value float64
+	fee   float64
+}
+
+// PositionLedger ведет учет позиции по исполнениям ордеров.
+// Безопасен для использования из нескольких горутин.
+type PositionLedger struct {
+	qtyPrecision int
+	position     Position
+	orders       map[string]orderExec
+	fundings     []FundingPayment
+	mu           sync.RWMutex
+}
+
+func NewPositionLedger(qtyPrecision int) *PositionLedger {
+	return &PositionLedger{
+		qtyPrecision: qtyPrecision,
+		orders:       make(map[string]orderExec),
+	}
+}
+
+// Apply учитывает исполнение и возвращает реализованный им PnL
+func (l *PositionLedger) Apply(fill Fill) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.apply(fill)
+}
+
+func (l *PositionLedger) apply(fill Fill) float64 {
+	if fill.Qty == 0 {
+		return 0
+	}
+
+	p := &l.position
+	p.Fees += fill.Fee
+	p.Fills++
+
+	var realized float64
+	if p.Qty == 0 || (p.Qty > 0) == (fill.Qty > 0) {
+		newQty := p.Qty + fill.Qty
+		p.AvgPrice = (p.AvgPrice*p.Qty + fill.Price*fill.Qty) / newQty
+		p.Qty = numeric.RoundFloat(newQty, l.qtyPrecision)
+	} else {
+		closedQty := min(math.Abs(fill.Qty), math.Abs(p.Qty))
+		if p.Qty > 0 {
+			realized = (fill.Price - p.AvgPrice) * closedQty
+		} else {
+			realized = (p.AvgPrice - fill.Price) * closedQty
+		}
+		p.RealizedPnL += realized
+
+		newQty := numeric.RoundFloat(p.Qty+fill.Qty, l.qtyPrecision)
+		if newQty != 0 && (newQty > 0) != (p.Qty > 0) {
+			// Переворот через ноль: остаток открывается по цене исполнения
+			p.AvgPrice = fill.Price
+		}
+		p.Qty = newQty
+	}
+	if p.Qty == 0 {
+		p.AvgPrice = 0
+	}
+
+	return realized
+}
+
+// ApplyOrder учитывает приращение исполнения ордера с момента предыдущего вызова
+// для того же ключа. Ордер содержит кумулятивные значения ExecQty, ExecValue и Fee.
+// Возвращает учтенное исполнение, реализованный им PnL и false, если новых исполнений нет.
+func (l *PositionLedger) ApplyOrder(key string, o *Order) (Fill, float64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prev := l.orders[key]
+	if o.IsClosed {
+		delete(l.orders, key)
+	} else {
+		l.orders[key] = orderExec{qty: o.ExecQty, value: o.ExecValue, fee: o.Fee}
+	}
+
+	dQty := numeric.RoundFloat(o.ExecQty-prev.qty, l.qtyPrecision)
+	if dQty == 0 {
+		return Fill{}, 0, false
+	}
+	fill := Fill{
+		Qty:   dQty,
+		Price: math.Abs((o.ExecValue - prev.value) / dQty),
+		Fee:   o.Fee - prev.fee,
+		Time:  o.UpdatedAt,
+	}
+	if fill.Price == 0 {
+		fill.Price = o.AvgPrice
+	}
+
+	return fill, l.apply(fill), true
+}
+
+// ApplyFunding учитывает расчет фандинга и сохраняет его в истории платежей
+func (l *PositionLedger) ApplyFunding(payment FundingPayment) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.position.Funding += payment.Amount
+	l.fundings = append(l.fundings, payment)
+}
+
+// FundingPayments возвращает копию истории платежей фандинга
+func (l *PositionLedger) FundingPayments() []FundingPayment {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]FundingPayment(nil), l.fundings...)
+}
+
+// Position возвращает снимок текущей позиции
+func (l *PositionLedger) Position() Position {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.position
+}
+
+// Qty возвращает текущее количество позиции со знаком
+func (l *PositionLedger) Qty() float64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.position.Qty
+}
+
+// Reset сбрасывает позицию и накопленные показатели
+func (l *PositionLedger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.position = Position{}
+	l.fundings = nil
+	clear(l.orders)
+}
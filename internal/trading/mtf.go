@@ -0,0 +1,183 @@
+package trading
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+// MultiFrameEvent - событие закрытия бара на одном или нескольких таймфреймах
+type MultiFrameEvent struct {
+	Time      int64                       // Время закрытия бара (мс)
+	Closed    []cdl.Interval              // Таймфреймы, закрывшие бар в момент Time
+	AllClosed bool                        // Бар закрыт на всех объявленных таймфреймах
+	Candles   map[cdl.Interval]cdl.Candle // Последние подтвержденные свечи каждого таймфрейма
+}
+
+// MultiFrame предоставляет согласованный по времени доступ к нескольким таймфреймам
+// одного инструмента. Свечи старших таймфреймов видны только после подтверждения.
+type MultiFrame struct {
+	subData   *SubData
+	symbol    string
+	intervals []cdl.Interval
+}
+
+// NewMultiFrame запускает синхронизацию свечей всех объявленных таймфреймов
+func NewMultiFrame(subData *SubData, symbol string, intervals ...cdl.Interval) (*MultiFrame, error) {
+	intervals = slices.Clone(intervals)
+	slices.Sort(intervals)
+	intervals = slices.Compact(intervals)
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("no intervals declared for %s", symbol)
+	}
+
+	for _, interval := range intervals {
+		if _, err := subData.getCandleSync(symbol, interval); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MultiFrame{
+		subData:   subData,
+		symbol:    symbol,
+		intervals: intervals,
+	}, nil
+}
+
+func (s *SubData) MultiFrame(symbol string, intervals ...cdl.Interval) (*MultiFrame, error) {
+	return NewMultiFrame(s, symbol, intervals...)
+}
+
+// Intervals возвращает объявленные таймфреймы по возрастанию
+func (m *MultiFrame) Intervals() []cdl.Interval {
+	return slices.Clone(m.intervals)
+}
+
+// View возвращает до limit подтвержденных свечей таймфрейма interval,
+// закрытых не позднее момента at (мс), что исключает заглядывание в будущее.
+func (m *MultiFrame) View(interval cdl.Interval, limit int, at int64) ([]cdl.Candle, error) {
+	if !slices.Contains(m.intervals, interval) {
+		return nil, fmt.Errorf("interval %d is not declared", interval)
+	}
+	candles, err := m.subData.ReadConfirmCandles(m.symbol, interval, limit+2)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(candles)
+	for n > 0 && interval.CloseTime(candles[n-1].Time) > at {
+		n--
+	}
+	return candles[max(0, n-limit):n], nil
+}
+
+// expected возвращает таймфреймы, бар которых закрывается в момент closeTime
+func (m *MultiFrame) expected(closeTime int64) int {
+	var n int
+	for _, interval := range m.intervals {
+		if interval.OpenTime(closeTime) == closeTime {
+			n++
+		}
+	}
+	return n
+}
+
+// Subscribe подписывает канал на события закрытия баров. Событие отправляется,
+// когда подтверждены все таймфреймы, закрывающие бар в данный момент.
+// Закрытие возвращенного канала отменяет подписку, после чего ch будет закрыт.
+func (m *MultiFrame) Subscribe(ch chan<- *MultiFrameEvent) (chan<- struct{}, error) {
+	done := make(chan struct{})
+	merged := make(chan *cdl.CandleStreamData)
+
+	var wg sync.WaitGroup
+	var subs []chan<- struct{}
+	for _, interval := range m.intervals {
+		in := make(chan *cdl.CandleStreamData, 4)
+		sub, err := m.subData.SubscribeChan(m.symbol, interval, in)
+		if err != nil {
+			for _, sub := range subs {
+				close(sub)
+			}
+			return nil, err
+		}
+		subs = append(subs, sub)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for data := range in {
+				if !data.Confirm {
+					continue
+				}
+				select {
+				case merged <- data:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	go func() {
+		defer close(ch)
+		defer func() {
+			for _, sub := range subs {
+				close(sub)
+			}
+		}()
+
+		latest := make(map[cdl.Interval]cdl.Candle, len(m.intervals))
+		pending := make(map[int64][]cdl.Interval)
+		for {
+			var data *cdl.CandleStreamData
+			select {
+			case <-done:
+				return
+			case d, ok := <-merged:
+				if !ok {
+					return
+				}
+				data = d
+			}
+
+			closeTime := data.Interval.CloseTime(data.Candle.Time)
+			latest[data.Interval] = data.Candle
+			closed := append(pending[closeTime], data.Interval)
+			if len(closed) < m.expected(closeTime) {
+				pending[closeTime] = closed
+				continue
+			}
+			// Незавершенные более ранние бары уже не будут дополнены
+			for t := range pending {
+				if t <= closeTime {
+					delete(pending, t)
+				}
+			}
+
+			slices.Sort(closed)
+			event := &MultiFrameEvent{
+				Time:      closeTime,
+				Closed:    closed,
+				AllClosed: len(closed) == len(m.intervals),
+				Candles:   make(map[cdl.Interval]cdl.Candle, len(latest)),
+			}
+			for interval, candle := range latest {
+				event.Candles[interval] = candle
+			}
+			select {
+			case ch <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return done, nil
+}
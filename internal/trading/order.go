@@ -1,6 +1,7 @@
 package trading
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -63,6 +64,7 @@ type OrderUpdate struct {
 	LinkId string `json:"linkId"`
 	Tag    string `json:"tag"`
 	Order  *Order `json:"order"`
+	Error  string `json:"error,omitempty"` // Ошибка размещения, ордер не создан
 }
 
 type OrderRequest struct {
@@ -73,6 +75,7 @@ type OrderRequest struct {
 	PlaceTimeout time.Duration       `json:"-"`
 	CloseTimeout time.Duration       `json:"-"`
 	Reply        chan<- *OrderUpdate `json:"-"`
+	Ctx          context.Context     `json:"-"`
 }
 
 func NewOrderRequest(order *Order, opts ...OrderRequestOption) *OrderRequest {
@@ -126,6 +129,13 @@ func WithReply(reply chan<- *OrderUpdate) OrderRequestOption {
 	}
 }
 
+// WithCancelContext отменяет незакрытый ордер досрочно при завершении ctx
+func WithCancelContext(ctx context.Context) OrderRequestOption {
+	return func(r *OrderRequest) {
+		r.Ctx = ctx
+	}
+}
+
 func (r *OrderRequest) Clone() *OrderRequest {
 	var clonedOrder *Order
 	if r.Order != nil {
@@ -139,5 +149,6 @@ func (r *OrderRequest) Clone() *OrderRequest {
 		PlaceTimeout: r.PlaceTimeout,
 		CloseTimeout: r.CloseTimeout,
 		Reply:        r.Reply,
+		Ctx:          r.Ctx,
 	}
 }
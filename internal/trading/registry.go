@@ -0,0 +1,82 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// StrategyConstructor создает стратегию из провалидированного объекта params
+type StrategyConstructor func(params json.RawMessage) (Strategy, error)
+
+// StrategyType описывает зарегистрированный тип стратегии
+type StrategyType struct {
+	Name   string
+	Schema ConfigSchema
+	New    StrategyConstructor
+}
+
+var (
+	registry   = make(map[string]*StrategyType)
+	registryMu sync.RWMutex
+)
+
+// RegisterStrategy регистрирует тип стратегии под именем name.
+// Повторная регистрация того же имени приводит к панике.
+func RegisterStrategy(name string, schema ConfigSchema, constructor StrategyConstructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || constructor == nil {
+		panic("trading: RegisterStrategy: empty name or nil constructor")
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("trading: RegisterStrategy: strategy type %q already registered", name))
+	}
+	registry[name] = &StrategyType{
+		Name:   name,
+		Schema: schema,
+		New:    constructor,
+	}
+}
+
+// LookupStrategy возвращает зарегистрированный тип стратегии по имени
+func LookupStrategy(name string) (*StrategyType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	t, ok := registry[name]
+	return t, ok
+}
+
+// RegisteredStrategies возвращает отсортированный список имен зарегистрированных стратегий
+func RegisteredStrategies() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// NewStrategy проверяет params по схеме типа cfg.Type и создает стратегию
+func NewStrategy(cfg *StrategyConfig) (Strategy, error) {
+	t, ok := LookupStrategy(cfg.Type)
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown strategy type %q (registered: %v)",
+			cfg.Type,
+			RegisteredStrategies(),
+		)
+	}
+	if err := t.Schema.Validate(cfg.Params); err != nil {
+		return nil, fmt.Errorf("invalid params for strategy type %q: %w", cfg.Type, err)
+	}
+
+	return t.New(cfg.Params)
+}
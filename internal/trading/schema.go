@@ -0,0 +1,185 @@
+package trading
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+// ParamType - тип параметра стратегии
+type ParamType string
+
+const (
+	ParamNumber     ParamType = "number"   // Число с плавающей точкой
+	ParamInteger    ParamType = "integer"  // Целое число
+	ParamString     ParamType = "string"   // Строка
+	ParamBool       ParamType = "bool"     // Логическое значение
+	ParamInterval   ParamType = "interval" // Интервал свечей (cdl.ParseInterval)
+	ParamNumberList ParamType = "[]number" // Массив чисел
+	ParamStringList ParamType = "[]string" // Массив строк
+)
+
+// ParamSpec описывает один параметр в схеме конфигурации стратегии
+type ParamSpec struct {
+	Name        string    `json:"name"`                  // Имя параметра в объекте params
+	Type        ParamType `json:"type"`                  // Тип значения
+	Required    bool      `json:"required"`              // Обязательный параметр
+	Min         *float64  `json:"min,omitempty"`         // Нижняя граница (для чисел и элементов массивов)
+	Max         *float64  `json:"max,omitempty"`         // Верхняя граница (для чисел и элементов массивов)
+	Enum        []string  `json:"enum,omitempty"`        // Допустимые значения строки
+	Description string    `json:"description,omitempty"` // Описание параметра
+}
+
+// ParamOption определяет тип функции для настройки ParamSpec
+type ParamOption func(*ParamSpec)
+
+// NewParam создает описание параметра
+func NewParam(name string, t ParamType, opts ...ParamOption) ParamSpec {
+	p := ParamSpec{Name: name, Type: t}
+	for _, option := range opts {
+		option(&p)
+	}
+	return p
+}
+
+// IsRequired помечает параметр как обязательный
+func IsRequired() ParamOption {
+	return func(p *ParamSpec) { p.Required = true }
+}
+
+// WithMin устанавливает нижнюю границу значения
+func WithMin(v float64) ParamOption {
+	return func(p *ParamSpec) { p.Min = &v }
+}
+
+// WithMax устанавливает верхнюю границу значения
+func WithMax(v float64) ParamOption {
+	return func(p *ParamSpec) { p.Max = &v }
+}
+
+// WithEnum ограничивает строковое значение списком вариантов
+func WithEnum(values ...string) ParamOption {
+	return func(p *ParamSpec) { p.Enum = values }
+}
+
+// WithDescription устанавливает описание параметра
+func WithDescription(d string) ParamOption {
+	return func(p *ParamSpec) { p.Description = d }
+}
+
+// ConfigSchema - схема параметров стратегии
+type ConfigSchema []ParamSpec
+
+// Validate проверяет объект params на соответствие схеме.
+// Неизвестные параметры считаются ошибкой.
+func (s ConfigSchema) Validate(params json.RawMessage) error {
+	fields := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(params)) > 0 {
+		if err := json.Unmarshal(params, &fields); err != nil {
+			return fmt.Errorf("params must be a json object: %w", err)
+		}
+	}
+
+	var errs []error
+	for name := range fields {
+		if !slices.ContainsFunc(s, func(p ParamSpec) bool { return p.Name == name }) {
+			errs = append(errs, fmt.Errorf("unknown parameter %q", name))
+		}
+	}
+	for _, p := range s {
+		raw, ok := fields[p.Name]
+		if !ok || string(raw) == "null" {
+			if p.Required {
+				errs = append(errs, fmt.Errorf("parameter %q is required", p.Name))
+			}
+			continue
+		}
+		if err := p.validate(raw); err != nil {
+			errs = append(errs, fmt.Errorf("parameter %q: %w", p.Name, err))
+		}
+	}
+	slices.SortFunc(errs, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+
+	return errors.Join(errs...)
+}
+
+func (p *ParamSpec) validate(raw json.RawMessage) error {
+	switch p.Type {
+	case ParamNumber, ParamInteger:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected %s", p.Type)
+		}
+		return p.checkNumber(v)
+	case ParamString:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected string")
+		}
+		return p.checkString(v)
+	case ParamBool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected bool")
+		}
+	case ParamInterval:
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected interval")
+		}
+		if _, err := cdl.ParseInterval(v); err != nil {
+			return err
+		}
+	case ParamNumberList:
+		var v []float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected array of numbers")
+		}
+		for i := range v {
+			if err := p.checkNumber(v[i]); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	case ParamStringList:
+		var v []string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("expected array of strings")
+		}
+		for i := range v {
+			if err := p.checkString(v[i]); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported parameter type %q", p.Type)
+	}
+	return nil
+}
+
+func (p *ParamSpec) checkNumber(v float64) error {
+	if p.Type == ParamInteger && v != math.Trunc(v) {
+		return fmt.Errorf("expected integer, got %v", v)
+	}
+	if p.Min != nil && v < *p.Min {
+		return fmt.Errorf("value %v is less than %v", v, *p.Min)
+	}
+	if p.Max != nil && v > *p.Max {
+		return fmt.Errorf("value %v is greater than %v", v, *p.Max)
+	}
+	return nil
+}
+
+func (p *ParamSpec) checkString(v string) error {
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, v) {
+		return fmt.Errorf("value %q is not one of %v", v, p.Enum)
+	}
+	return nil
+}
@@ -0,0 +1,491 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+const (
+	DCAStrategyType = "dca"
+
+	dcaTagBuy    = "dca-buy"
+	dcaTagSafety = "dca-safety"
+	dcaTagTP     = "dca-tp"
+
+	// dcaExitTimeout - время, после которого состояние ордера тейк-профита без ответа
+	// запрашивается у биржи
+	dcaExitTimeout = 30 * time.Second
+)
+
+type DCAConfig struct {
+	Symbol          string    `json:"symbol"`
+	Interval        string    `json:"interval"`
+	EveryCandles    int       `json:"everyCandles"`
+	OrderAmt        float64   `json:"orderAmt"`
+	MaxAmt          *float64  `json:"maxAmt"`
+	SafetyDrawdowns []float64 `json:"safetyDrawdowns"`
+	SafetyOrderAmt  *float64  `json:"safetyOrderAmt"`
+	SafetyScale     *float64  `json:"safetyScale"`
+	TakeProfit      *float64  `json:"takeProfit"`
+	CloseOnStop     bool      `json:"closeOnStop"`
+}
+
+var dcaConfigSchema = trading.ConfigSchema{
+	trading.NewParam("symbol", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("interval", trading.ParamInterval, trading.IsRequired()),
+	trading.NewParam("everyCandles", trading.ParamInteger, trading.IsRequired(), trading.WithMin(1)),
+	trading.NewParam("orderAmt", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("maxAmt", trading.ParamNumber, trading.WithMin(0),
+		trading.WithDescription("maximum quote amount invested per cycle")),
+	trading.NewParam("safetyDrawdowns", trading.ParamNumberList, trading.WithMin(0), trading.WithMax(1),
+		trading.WithDescription("drawdowns from the cycle entry price that trigger safety orders")),
+	trading.NewParam("safetyOrderAmt", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("safetyScale", trading.ParamNumber, trading.WithMin(1)),
+	trading.NewParam("takeProfit", trading.ParamNumber, trading.WithMin(0),
+		trading.WithDescription("profit ratio over the average entry price to close the position")),
+	trading.NewParam("closeOnStop", trading.ParamBool),
+}
+
+func init() {
+	trading.RegisterStrategy(
+		DCAStrategyType,
+		dcaConfigSchema,
+		func(params json.RawMessage) (trading.Strategy, error) {
+			var cfg DCAConfig
+			if err := json.Unmarshal(params, &cfg); err != nil {
+				return nil, err
+			}
+			return NewDCAStrategy(&cfg)
+		},
+	)
+}
+
+// DCAStrategy покупает фиксированную сумму каждые everyCandles свечей,
+// докупает на просадках и закрывает усредненную позицию по тейк-профиту.
+type DCAStrategy struct {
+	symbol       string
+	interval     cdl.Interval
+	everyCandles int
+	orderAmt     float64
+	maxAmt       float64
+	safetyLevels []float64
+	safetyAmts   []float64
+	takeProfit   float64
+	closeOnStop  bool
+
+	qtyPrecision int
+	minOrderAmt  float64
+	isSpot       bool
+
+	ctx              context.Context
+	subData          *trading.SubData
+	orderRequestChan chan<- *trading.OrderRequest
+
+	candleStreamChan chan *cdl.CandleStreamData
+	candleStream     chan<- struct{}
+	orderUpdateChan  chan *trading.OrderUpdate
+	done             chan struct{}
+
+	ledger *trading.PositionLedger
+
+	candleCount int
+	basePrice   float64 // Цена первого исполнения текущего цикла
+	nextSafety  int
+	investedAmt float64
+	feeQty      float64 // Комиссии, списанные в базовой монете (спот)
+	exiting     bool    // Ордер тейк-профита отправлен и еще не закрыт
+	exit        dcaExit // Текущий ордер тейк-профита
+	mu          sync.Mutex
+
+	isWorking atomic.Bool
+}
+
+// dcaExit - ордер тейк-профита, ответ о закрытии которого ожидает стратегия
+type dcaExit struct {
+	linkId  string
+	orderId string // ID ордера из ответа о размещении
+	checkAt time.Time
+}
+
+func NewDCAStrategy(cfg *DCAConfig) (*DCAStrategy, error) {
+	if cfg.Symbol == "" {
+		return nil, fmt.Errorf("symbol not specified in configuration parameters")
+	}
+	interval, err := cdl.ParseInterval(cfg.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EveryCandles < 1 {
+		return nil, fmt.Errorf("everyCandles must be positive: %d", cfg.EveryCandles)
+	}
+	if cfg.OrderAmt <= 0 {
+		return nil, fmt.Errorf("order amt must be positive: %f", cfg.OrderAmt)
+	}
+
+	var maxAmt float64
+	if cfg.MaxAmt != nil {
+		maxAmt = *cfg.MaxAmt
+	}
+
+	safetyOrderAmt := cfg.OrderAmt
+	if cfg.SafetyOrderAmt != nil {
+		safetyOrderAmt = *cfg.SafetyOrderAmt
+	}
+	safetyScale := 1.
+	if cfg.SafetyScale != nil {
+		safetyScale = max(1, *cfg.SafetyScale)
+	}
+	safetyAmts := make([]float64, len(cfg.SafetyDrawdowns))
+	for i := range cfg.SafetyDrawdowns {
+		if i > 0 && cfg.SafetyDrawdowns[i] <= cfg.SafetyDrawdowns[i-1] {
+			return nil, fmt.Errorf("safety drawdowns must be increasing: %v", cfg.SafetyDrawdowns)
+		}
+		safetyAmts[i] = safetyOrderAmt * math.Pow(safetyScale, float64(i))
+	}
+
+	var takeProfit float64
+	if cfg.TakeProfit != nil {
+		takeProfit = *cfg.TakeProfit
+	}
+
+	s := &DCAStrategy{
+		symbol:       cfg.Symbol,
+		interval:     interval,
+		everyCandles: cfg.EveryCandles,
+		orderAmt:     cfg.OrderAmt,
+		maxAmt:       maxAmt,
+		safetyLevels: cfg.SafetyDrawdowns,
+		safetyAmts:   safetyAmts,
+		takeProfit:   takeProfit,
+		closeOnStop:  cfg.CloseOnStop,
+	}
+
+	return s, nil
+}
+
+func (s *DCAStrategy) Init(ctx context.Context, subData *trading.SubData, req chan<- *trading.OrderRequest) {
+	s.ctx = ctx
+	s.subData = subData
+	s.orderRequestChan = req
+
+	go func() {
+		<-s.ctx.Done()
+		s.Stop()
+	}()
+}
+
+func (s *DCAStrategy) Launch() (err error) {
+	if !s.isWorking.CompareAndSwap(false, true) {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			s.isWorking.Store(false)
+		}
+	}()
+
+	instrumentInfo, err := s.subData.GetInstrumentInfo(s.symbol)
+	if err != nil {
+		return err
+	}
+	s.qtyPrecision = instrumentInfo.QtyPrecision
+	s.minOrderAmt = instrumentInfo.MinOrderAmt
+	s.isSpot = instrumentInfo.Category == "spot"
+
+	if s.orderAmt < s.minOrderAmt {
+		err = fmt.Errorf(
+			"order amt is less than the minimum order amt: %f < %f",
+			s.orderAmt,
+			s.minOrderAmt,
+		)
+		return err
+	}
+	for _, amt := range s.safetyAmts {
+		if amt < s.minOrderAmt {
+			err = fmt.Errorf(
+				"safety order amt is less than the minimum order amt: %f < %f",
+				amt,
+				s.minOrderAmt,
+			)
+			return err
+		}
+	}
+
+	s.candleStreamChan = make(chan *cdl.CandleStreamData)
+	done, err := s.subData.SubscribeChan(s.symbol, s.interval, s.candleStreamChan)
+	if err != nil {
+		close(s.candleStreamChan)
+		return err
+	}
+	s.candleStream = done
+	s.orderUpdateChan = make(chan *trading.OrderUpdate)
+	s.done = make(chan struct{})
+	s.ledger = trading.NewPositionLedger(s.qtyPrecision)
+
+	s.mu.Lock()
+	s.candleCount = 0
+	s.feeQty = 0
+	s.resetCycle()
+	s.mu.Unlock()
+
+	go s.orderUpdate()
+	go s.observe()
+
+	return nil
+}
+
+// Symbols возвращает символ стратегии
+func (s *DCAStrategy) Symbols() []string {
+	return []string{s.symbol}
+}
+
+func (s *DCAStrategy) Stop() bool {
+	if !s.isWorking.CompareAndSwap(true, false) {
+		return false
+	}
+
+	close(s.candleStream)
+	if s.closeOnStop {
+		if qty := s.sellableQty(); qty != 0 {
+			s.orderRequestChan <- trading.NewOrderRequest(
+				trading.NewOrder(s.symbol, -qty, nil),
+			)
+		}
+	}
+	close(s.done)
+
+	return true
+}
+
+// Position возвращает текущее состояние позиции стратегии
+func (s *DCAStrategy) Position() trading.Position {
+	return s.ledger.Position()
+}
+
+// resetCycle начинает новый цикл накопления. Вызывается под s.mu.
+func (s *DCAStrategy) resetCycle() {
+	s.basePrice = 0
+	s.nextSafety = 0
+	s.investedAmt = 0
+	s.exiting = false
+	s.exit = dcaExit{}
+}
+
+// sellableQty возвращает количество, доступное для продажи с учетом комиссий в базовой монете
+func (s *DCAStrategy) sellableQty() float64 {
+	s.mu.Lock()
+	feeQty := s.feeQty
+	s.mu.Unlock()
+
+	return numeric.TruncateFloat(s.ledger.Qty()-feeQty, s.qtyPrecision)
+}
+
+func (s *DCAStrategy) observe() {
+	for data := range s.candleStreamChan {
+		price := data.Candle.C
+		s.checkTakeProfit(price)
+		s.checkSafety(price)
+		if data.Confirm {
+			s.checkSchedule(price)
+		}
+	}
+}
+
+// checkSchedule выставляет плановую покупку каждые everyCandles подтвержденных свечей
+func (s *DCAStrategy) checkSchedule(price float64) {
+	s.mu.Lock()
+	count := s.candleCount
+	s.candleCount++
+	if count%s.everyCandles != 0 || s.exiting {
+		s.mu.Unlock()
+		return
+	}
+	amt := s.orderAmt
+	if s.maxAmt > 0 && s.investedAmt+amt > s.maxAmt {
+		s.mu.Unlock()
+		return
+	}
+	s.investedAmt += amt
+	s.mu.Unlock()
+
+	s.buy(amt, price, dcaTagBuy)
+}
+
+// checkSafety выставляет страховые покупки при просадке от цены входа цикла
+func (s *DCAStrategy) checkSafety(price float64) {
+	s.mu.Lock()
+	if s.basePrice == 0 || s.exiting || s.nextSafety >= len(s.safetyLevels) {
+		s.mu.Unlock()
+		return
+	}
+	if price > s.basePrice*(1-s.safetyLevels[s.nextSafety]) {
+		s.mu.Unlock()
+		return
+	}
+	amt := s.safetyAmts[s.nextSafety]
+	s.nextSafety++
+	if s.maxAmt > 0 && s.investedAmt+amt > s.maxAmt {
+		s.mu.Unlock()
+		return
+	}
+	s.investedAmt += amt
+	s.mu.Unlock()
+
+	s.buy(amt, price, dcaTagSafety)
+}
+
+// checkTakeProfit закрывает позицию при достижении целевой прибыли над средней ценой
+func (s *DCAStrategy) checkTakeProfit(price float64) {
+	if s.takeProfit <= 0 {
+		return
+	}
+	position := s.ledger.Position()
+	if position.Qty <= 0 || price < position.AvgPrice*(1+s.takeProfit) {
+		return
+	}
+	qty := s.sellableQty()
+	if qty*price < s.minOrderAmt {
+		return
+	}
+
+	s.mu.Lock()
+	// Блокировка снимается ответом с ошибкой размещения, закрытием ордера
+	// или проверкой его состояния, если ответ не пришел за dcaExitTimeout
+	if s.exiting {
+		expired := time.Now().After(s.exit.checkAt)
+		s.mu.Unlock()
+		if expired {
+			s.recoverExit()
+		}
+		return
+	}
+	s.exiting = true
+	s.exit = dcaExit{
+		linkId:  uuid.NewString(),
+		checkAt: time.Now().Add(dcaExitTimeout),
+	}
+	linkId := s.exit.linkId
+	s.mu.Unlock()
+
+	s.sendOrder(-qty, dcaTagTP, linkId)
+}
+
+// recoverExit запрашивает у биржи состояние ордера тейк-профита, ответ о закрытии
+// которого потерян или не отправлен из-за неудачной отмены. Если ID ордера неизвестен,
+// блокировка снимается: ответ о размещении не пришел и ордер не отслеживается.
+func (s *DCAStrategy) recoverExit() {
+	s.mu.Lock()
+	exit := s.exit
+	s.exit.checkAt = time.Now().Add(dcaExitTimeout)
+	s.mu.Unlock()
+
+	if exit.orderId == "" {
+		log.Printf("dca take profit for %s has no reply, lock released", s.symbol)
+		s.mu.Lock()
+		if s.exit.linkId == exit.linkId {
+			s.exiting = false
+		}
+		s.mu.Unlock()
+		return
+	}
+	order, err := s.subData.GetOrder(exit.orderId)
+	if err != nil {
+		log.Printf("dca take profit %s for %s is not available: %v", exit.orderId, s.symbol, err)
+		return
+	}
+	s.handleOrderUpdate(&trading.OrderUpdate{
+		LinkId: exit.linkId,
+		Tag:    dcaTagTP,
+		Order:  order,
+	})
+}
+
+func (s *DCAStrategy) buy(amt, price float64, tag string) {
+	qty := numeric.TruncateFloat(amt/price, s.qtyPrecision)
+	if qty*price < s.minOrderAmt {
+		log.Printf("qty less than minimum limit: %f < %f", qty*price, s.minOrderAmt)
+		return
+	}
+	s.sendOrder(qty, tag, uuid.NewString())
+}
+
+func (s *DCAStrategy) sendOrder(qty float64, tag, linkId string) {
+	if !s.isWorking.Load() {
+		return
+	}
+	s.orderRequestChan <- trading.NewOrderRequest(
+		trading.NewOrder(s.symbol, qty, nil),
+		trading.WithLinkId(linkId),
+		trading.WithTag(tag),
+		trading.WithReply(s.orderUpdateChan),
+	)
+}
+
+func (s *DCAStrategy) orderUpdate() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case update := <-s.orderUpdateChan:
+			s.handleOrderUpdate(update)
+		}
+	}
+}
+
+func (s *DCAStrategy) handleOrderUpdate(update *trading.OrderUpdate) {
+	if update.Error != "" {
+		log.Printf("dca order for %s was not placed: %s", s.symbol, update.Error)
+		if update.Tag == dcaTagTP {
+			s.mu.Lock()
+			if update.LinkId == s.exit.linkId {
+				s.exiting = false
+			}
+			s.mu.Unlock()
+		}
+		return
+	}
+	if update.Order.ID == "" {
+		return
+	}
+	fill, _, ok := s.ledger.ApplyOrder(update.LinkId, update.Order)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ok && fill.Qty > 0 {
+		if s.basePrice == 0 {
+			s.basePrice = fill.Price
+		}
+		if s.isSpot && fill.Price > 0 {
+			s.feeQty += fill.Fee / fill.Price
+		}
+	}
+	if update.Tag != dcaTagTP || update.LinkId != s.exit.linkId {
+		return
+	}
+	if !update.Order.IsClosed {
+		s.exit.orderId = update.Order.ID
+		return
+	}
+
+	// Позиция считается закрытой, если непроданный остаток меньше шага количества
+	rest := s.ledger.Qty() - s.feeQty
+	if rest < math.Pow(10, -float64(s.qtyPrecision)) {
+		s.resetCycle()
+		log.Printf("dca cycle for %s closed: %+v", s.symbol, s.ledger.Position())
+		return
+	}
+	s.exiting = false
+}
@@ -0,0 +1,551 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+const (
+	GridStrategyType = "grid"
+
+	GridArithmetic = "arithmetic"
+	GridGeometric  = "geometric"
+
+	GridExitStop     = "stop"
+	GridExitRecenter = "recenter"
+)
+
+type GridConfig struct {
+	Symbol      string  `json:"symbol"`
+	Interval    string  `json:"interval"`
+	LowerPrice  float64 `json:"lowerPrice"`
+	UpperPrice  float64 `json:"upperPrice"`
+	Levels      int     `json:"levels"`
+	Spacing     string  `json:"spacing"`
+	OrderAmt    float64 `json:"orderAmt"`
+	OnExit      string  `json:"onExit"`
+	OrderTTL    *int    `json:"orderTtl"`
+	CloseOnStop *bool   `json:"closeOnStop"`
+}
+
+var gridConfigSchema = trading.ConfigSchema{
+	trading.NewParam("symbol", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("interval", trading.ParamInterval),
+	trading.NewParam("lowerPrice", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("upperPrice", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("levels", trading.ParamInteger, trading.IsRequired(), trading.WithMin(2), trading.WithMax(200)),
+	trading.NewParam("spacing", trading.ParamString, trading.WithEnum(GridArithmetic, GridGeometric)),
+	trading.NewParam("orderAmt", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("onExit", trading.ParamString, trading.WithEnum(GridExitStop, GridExitRecenter)),
+	trading.NewParam("orderTtl", trading.ParamInteger, trading.WithMin(1),
+		trading.WithDescription("order lifetime in minutes before it is re-placed")),
+	trading.NewParam("closeOnStop", trading.ParamBool),
+}
+
+func init() {
+	trading.RegisterStrategy(
+		GridStrategyType,
+		gridConfigSchema,
+		func(params json.RawMessage) (trading.Strategy, error) {
+			var cfg GridConfig
+			if err := json.Unmarshal(params, &cfg); err != nil {
+				return nil, err
+			}
+			return NewGridStrategy(&cfg)
+		},
+	)
+}
+
+// gridLevel - уровень сетки и активный ордер на нем
+type gridLevel struct {
+	price  float64
+	side   float64 // 1 - покупка, -1 - продажа, 0 - ордера нет
+	qty    float64
+	linkId string
+	order  *trading.Order // Ордер запроса, ID заполняется ботом при размещении
+	sentAt int64
+	acked  bool
+}
+
+type GridStrategy struct {
+	symbol      string
+	interval    cdl.Interval
+	lowerPrice  float64
+	upperPrice  float64
+	levelsCount int
+	spacing     string
+	orderAmt    float64
+	onExit      string
+	orderTTL    time.Duration
+	closeOnStop bool
+
+	qtyPrecision int
+	minOrderAmt  float64
+	tickSize     float64
+
+	ctx              context.Context
+	subData          *trading.SubData
+	orderRequestChan chan<- *trading.OrderRequest
+
+	candleStreamChan chan *cdl.CandleStreamData
+	candleStream     chan<- struct{}
+	orderUpdateChan  chan *trading.OrderUpdate
+	done             chan struct{}
+
+	ledger *trading.PositionLedger
+
+	levels     []gridLevel
+	gridCtx    context.Context
+	gridCancel context.CancelFunc
+	mu         sync.Mutex
+
+	lastPrice atomic.Pointer[float64]
+	isWorking atomic.Bool
+}
+
+func NewGridStrategy(cfg *GridConfig) (*GridStrategy, error) {
+	if cfg.Symbol == "" {
+		return nil, fmt.Errorf("symbol not specified in configuration parameters")
+	}
+	if cfg.LowerPrice <= 0 || cfg.UpperPrice <= cfg.LowerPrice {
+		return nil, fmt.Errorf(
+			"invalid grid bounds: lower %f, upper %f",
+			cfg.LowerPrice,
+			cfg.UpperPrice,
+		)
+	}
+	if cfg.Levels < 2 {
+		return nil, fmt.Errorf("grid requires at least 2 levels: %d", cfg.Levels)
+	}
+	if cfg.OrderAmt <= 0 {
+		return nil, fmt.Errorf("order amt must be positive: %f", cfg.OrderAmt)
+	}
+
+	interval := cdl.M1
+	if cfg.Interval != "" {
+		var err error
+		if interval, err = cdl.ParseInterval(cfg.Interval); err != nil {
+			return nil, err
+		}
+	}
+
+	spacing := cfg.Spacing
+	switch spacing {
+	case "":
+		spacing = GridArithmetic
+	case GridArithmetic, GridGeometric:
+	default:
+		return nil, fmt.Errorf("unknown grid spacing: %s", spacing)
+	}
+
+	onExit := cfg.OnExit
+	switch onExit {
+	case "":
+		onExit = GridExitStop
+	case GridExitStop, GridExitRecenter:
+	default:
+		return nil, fmt.Errorf("unknown grid exit mode: %s", onExit)
+	}
+
+	orderTTL := 24 * time.Hour
+	if cfg.OrderTTL != nil && *cfg.OrderTTL > 0 {
+		orderTTL = time.Duration(*cfg.OrderTTL) * time.Minute
+	}
+
+	closeOnStop := true
+	if cfg.CloseOnStop != nil {
+		closeOnStop = *cfg.CloseOnStop
+	}
+
+	s := &GridStrategy{
+		symbol:      cfg.Symbol,
+		interval:    interval,
+		lowerPrice:  cfg.LowerPrice,
+		upperPrice:  cfg.UpperPrice,
+		levelsCount: cfg.Levels,
+		spacing:     spacing,
+		orderAmt:    cfg.OrderAmt,
+		onExit:      onExit,
+		orderTTL:    orderTTL,
+		closeOnStop: closeOnStop,
+	}
+
+	return s, nil
+}
+
+func (s *GridStrategy) Init(ctx context.Context, subData *trading.SubData, req chan<- *trading.OrderRequest) {
+	s.ctx = ctx
+	s.subData = subData
+	s.orderRequestChan = req
+
+	go func() {
+		<-s.ctx.Done()
+		s.Stop()
+	}()
+}
+
+func (s *GridStrategy) Launch() (err error) {
+	if !s.isWorking.CompareAndSwap(false, true) {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			s.isWorking.Store(false)
+		}
+	}()
+
+	instrumentInfo, err := s.subData.GetInstrumentInfo(s.symbol)
+	if err != nil {
+		return err
+	}
+	s.qtyPrecision = instrumentInfo.QtyPrecision
+	s.minOrderAmt = instrumentInfo.MinOrderAmt
+	s.tickSize = instrumentInfo.TickSize
+
+	levels, err := s.buildLevels(s.lowerPrice, s.upperPrice)
+	if err != nil {
+		return err
+	}
+
+	lastCandle, err := s.subData.ReadConfirmCandles(s.symbol, s.interval, 1)
+	if err != nil {
+		return err
+	}
+	if len(lastCandle) == 0 {
+		err = fmt.Errorf("no confirmed candles for %s", s.symbol)
+		return err
+	}
+	price := lastCandle[0].C
+	if price < s.lowerPrice || price > s.upperPrice {
+		err = fmt.Errorf(
+			"last price %f is outside the grid range [%f, %f]",
+			price,
+			s.lowerPrice,
+			s.upperPrice,
+		)
+		return err
+	}
+	s.lastPrice.Store(&price)
+
+	s.candleStreamChan = make(chan *cdl.CandleStreamData)
+	done, err := s.subData.SubscribeChan(s.symbol, s.interval, s.candleStreamChan)
+	if err != nil {
+		close(s.candleStreamChan)
+		return err
+	}
+	s.candleStream = done
+	s.orderUpdateChan = make(chan *trading.OrderUpdate)
+	s.done = make(chan struct{})
+	s.ledger = trading.NewPositionLedger(s.qtyPrecision)
+
+	s.mu.Lock()
+	s.levels = levels
+	s.gridCtx, s.gridCancel = context.WithCancel(s.ctx)
+	s.mu.Unlock()
+
+	go s.orderUpdate()
+	go s.observe()
+
+	s.placeLadder(price)
+
+	return nil
+}
+
+// Symbols возвращает символ стратегии
+func (s *GridStrategy) Symbols() []string {
+	return []string{s.symbol}
+}
+
+func (s *GridStrategy) Stop() bool {
+	if !s.isWorking.CompareAndSwap(true, false) {
+		return false
+	}
+
+	s.mu.Lock()
+	s.gridCancel()
+	s.mu.Unlock()
+	close(s.candleStream)
+
+	if s.closeOnStop {
+		// Даем боту отменить ордера сетки и вернуть итоговые исполнения
+		time.Sleep(time.Second)
+		if qty := s.ledger.Qty(); qty != 0 {
+			s.orderRequestChan <- trading.NewOrderRequest(
+				trading.NewOrder(s.symbol, -qty, nil),
+			)
+		}
+	}
+	close(s.done)
+
+	return true
+}
+
+// Position возвращает текущее состояние позиции стратегии
+func (s *GridStrategy) Position() trading.Position {
+	return s.ledger.Position()
+}
+
+// buildLevels рассчитывает цены уровней сетки с учетом шага цены инструмента
+func (s *GridStrategy) buildLevels(lower, upper float64) ([]gridLevel, error) {
+	n := s.levelsCount
+	levels := make([]gridLevel, n)
+	for i := range levels {
+		var price float64
+		t := float64(i) / float64(n-1)
+		if s.spacing == GridGeometric {
+			price = lower * math.Pow(upper/lower, t)
+		} else {
+			price = lower + (upper-lower)*t
+		}
+		price = numeric.RoundToStep(price, s.tickSize)
+		if i > 0 && price <= levels[i-1].price {
+			return nil, fmt.Errorf("grid step is less than the tick size: %f", s.tickSize)
+		}
+		if qty := s.levelQty(price); qty*price < s.minOrderAmt {
+			return nil, fmt.Errorf(
+				"grid order amt at level %f is less than the minimum order amt: %f < %f",
+				price,
+				qty*price,
+				s.minOrderAmt,
+			)
+		}
+		levels[i].price = price
+	}
+
+	return levels, nil
+}
+
+func (s *GridStrategy) levelQty(price float64) float64 {
+	return numeric.TruncateFloat(s.orderAmt/price, s.qtyPrecision)
+}
+
+// placeLadder выставляет покупки ниже цены и продажи выше нее.
+// Ближайший к цене уровень остается свободным.
+func (s *GridStrategy) placeLadder(price float64) {
+	s.mu.Lock()
+	nearest := 0
+	for i := range s.levels {
+		if math.Abs(s.levels[i].price-price) < math.Abs(s.levels[nearest].price-price) {
+			nearest = i
+		}
+	}
+	var requests []*trading.OrderRequest
+	for i := range s.levels {
+		if i == nearest {
+			continue
+		}
+		side := 1.
+		if i > nearest {
+			side = -1
+		}
+		qty := side * s.levelQty(s.levels[i].price)
+		requests = append(requests, s.newLevelRequest(i, qty))
+	}
+	s.mu.Unlock()
+
+	s.sendRequests(requests)
+}
+
+// newLevelRequest создает запрос ордера на уровне i. Вызывается под s.mu.
+func (s *GridStrategy) newLevelRequest(i int, qty float64) *trading.OrderRequest {
+	lvl := &s.levels[i]
+	lvl.side = 1
+	if qty < 0 {
+		lvl.side = -1
+	}
+	lvl.qty = qty
+	lvl.linkId = uuid.NewString()
+	lvl.sentAt = time.Now().UnixMilli()
+	lvl.acked = false
+
+	price := lvl.price
+	lvl.order = trading.NewOrder(s.symbol, qty, &price)
+	return trading.NewOrderRequest(
+		lvl.order,
+		trading.WithLinkId(lvl.linkId),
+		trading.WithTag(GridStrategyType),
+		trading.WithReply(s.orderUpdateChan),
+		trading.WithCloseTimeout(s.orderTTL),
+		trading.WithCancelContext(s.gridCtx),
+	)
+}
+
+func (s *GridStrategy) sendRequests(requests []*trading.OrderRequest) {
+	for _, req := range requests {
+		if !s.isWorking.Load() {
+			return
+		}
+		s.orderRequestChan <- req
+	}
+}
+
+func (s *GridStrategy) orderUpdate() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case update := <-s.orderUpdateChan:
+			s.handleOrderUpdate(update)
+		}
+	}
+}
+
+func (s *GridStrategy) handleOrderUpdate(update *trading.OrderUpdate) {
+	if update.Order.ID != "" {
+		s.ledger.ApplyOrder(update.LinkId, update.Order)
+	}
+
+	s.mu.Lock()
+	idx := -1
+	for i := range s.levels {
+		if s.levels[i].linkId == update.LinkId {
+			idx = i
+			break
+		}
+	}
+	// Ответ по ордеру предыдущей сетки учитывается только в журнале
+	if idx == -1 {
+		s.mu.Unlock()
+		return
+	}
+	lvl := &s.levels[idx]
+	// Неразмещенный ордер будет выставлен повторно в retryUnacked
+	if update.Error != "" {
+		s.mu.Unlock()
+		return
+	}
+	if !update.Order.IsClosed {
+		lvl.acked = true
+		s.mu.Unlock()
+		return
+	}
+
+	side := lvl.side
+	lvl.side, lvl.qty, lvl.linkId, lvl.order = 0, 0, "", nil
+
+	var requests []*trading.OrderRequest
+	if s.gridCtx.Err() == nil {
+		// Исполненный объем переносится на соседний уровень в противоположную сторону
+		execQty := numeric.RoundFloat(update.Order.ExecQty, s.qtyPrecision)
+		if j := idx + int(side); execQty != 0 && j >= 0 && j < len(s.levels) {
+			if s.levels[j].linkId != "" {
+				log.Printf("grid level %f is busy, fill at %f is not mirrored", s.levels[j].price, lvl.price)
+			} else if math.Abs(execQty)*s.levels[j].price < s.minOrderAmt {
+				log.Printf("grid mirror qty less than minimum limit: %f", execQty)
+			} else {
+				requests = append(requests, s.newLevelRequest(j, -execQty))
+			}
+		}
+		// Неисполненный остаток (например, после истечения orderTtl) выставляется повторно
+		remaining := numeric.RoundFloat(update.Order.Qty-update.Order.ExecQty, s.qtyPrecision)
+		if remaining != 0 && math.Abs(remaining)*lvl.price >= s.minOrderAmt {
+			requests = append(requests, s.newLevelRequest(idx, remaining))
+		}
+	}
+	s.mu.Unlock()
+
+	s.sendRequests(requests)
+}
+
+func (s *GridStrategy) observe() {
+	for data := range s.candleStreamChan {
+		price := data.Candle.C
+		s.lastPrice.Store(&price)
+
+		s.mu.Lock()
+		lower, upper := s.levels[0].price, s.levels[len(s.levels)-1].price
+		s.mu.Unlock()
+
+		if price < lower || price > upper {
+			s.exitRange(price)
+			continue
+		}
+		if data.Confirm {
+			s.retryUnacked()
+		}
+	}
+}
+
+// exitRange обрабатывает выход цены за границы сетки
+func (s *GridStrategy) exitRange(price float64) {
+	if !s.isWorking.Load() {
+		return
+	}
+	if s.onExit == GridExitStop {
+		log.Printf("price %f left the grid range, stopping grid for %s", price, s.symbol)
+		go s.Stop()
+		return
+	}
+
+	s.mu.Lock()
+	lower, upper := s.levels[0].price, s.levels[len(s.levels)-1].price
+	if s.spacing == GridGeometric {
+		r := math.Sqrt(upper / lower)
+		lower, upper = price/r, price*r
+	} else {
+		half := (upper - lower) / 2
+		lower, upper = price-half, price+half
+	}
+	var levels []gridLevel
+	err := fmt.Errorf("lower bound is not positive: %f", lower)
+	if lower > 0 {
+		levels, err = s.buildLevels(lower, upper)
+	}
+	if err != nil {
+		s.mu.Unlock()
+		log.Printf("grid recenter error at price %f: %v, stopping grid", price, err)
+		go s.Stop()
+		return
+	}
+	// Ордера текущей сетки отменяются, накопленная позиция сохраняется
+	s.gridCancel()
+	s.levels = levels
+	s.gridCtx, s.gridCancel = context.WithCancel(s.ctx)
+	s.mu.Unlock()
+
+	log.Printf("grid for %s recentered at %f: [%f, %f]", s.symbol, price, lower, upper)
+	s.placeLadder(price)
+}
+
+// retryUnacked повторно выставляет ордера, размещение которых не подтвердилось.
+// Ответ о размещении может быть потерян, поэтому уровень, ордеру которого бот
+// присвоил ID, считается подтвержденным и повторно не выставляется.
+func (s *GridStrategy) retryUnacked() {
+	deadline := time.Now().Add(-10 * time.Second).UnixMilli()
+
+	s.mu.Lock()
+	var requests []*trading.OrderRequest
+	for i := range s.levels {
+		lvl := &s.levels[i]
+		if lvl.linkId == "" || lvl.acked || lvl.sentAt > deadline {
+			continue
+		}
+		if lvl.order != nil && placedOrderId(lvl.order) != "" {
+			lvl.acked = true
+			continue
+		}
+		requests = append(requests, s.newLevelRequest(i, lvl.qty))
+	}
+	s.mu.Unlock()
+
+	s.sendRequests(requests)
+}
+
+// placedOrderId возвращает ID, присвоенный ордеру при размещении
+func placedOrderId(o *trading.Order) string {
+	o.Lock()
+	defer o.Unlock()
+	return o.ID
+}
@@ -0,0 +1,428 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/utils/norm"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+const (
+	MeanReversionStrategyType = "meanReversion"
+
+	BandsZScore    = "zscore"
+	BandsBollinger = "bollinger"
+)
+
+type MeanReversionConfig struct {
+	Symbol      string   `json:"symbol"`
+	Interval    string   `json:"interval"`
+	Arg         string   `json:"arg"`
+	Bands       string   `json:"bands"`
+	Period      int      `json:"period"`
+	EntryZ      *float64 `json:"entryZ"`
+	ExitZ       *float64 `json:"exitZ"`
+	StopZ       *float64 `json:"stopZ"`
+	StopLoss    *float64 `json:"stopLoss"`
+	OrderAmt    float64  `json:"orderAmt"`
+	AllowShort  *bool    `json:"allowShort"`
+	CloseOnStop *bool    `json:"closeOnStop"`
+}
+
+var meanReversionConfigSchema = trading.ConfigSchema{
+	trading.NewParam("symbol", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("interval", trading.ParamInterval, trading.IsRequired()),
+	trading.NewParam("arg", trading.ParamString,
+		trading.WithDescription("candle arg the bands are built on (cdl.CandleArg), default C")),
+	trading.NewParam("bands", trading.ParamString, trading.WithEnum(BandsZScore, BandsBollinger)),
+	trading.NewParam("period", trading.ParamInteger, trading.IsRequired(), trading.WithMin(2), trading.WithMax(1000)),
+	trading.NewParam("entryZ", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("exitZ", trading.ParamNumber),
+	trading.NewParam("stopZ", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("stopLoss", trading.ParamNumber, trading.WithMin(0), trading.WithMax(1)),
+	trading.NewParam("orderAmt", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("allowShort", trading.ParamBool),
+	trading.NewParam("closeOnStop", trading.ParamBool),
+}
+
+func init() {
+	trading.RegisterStrategy(
+		MeanReversionStrategyType,
+		meanReversionConfigSchema,
+		func(params json.RawMessage) (trading.Strategy, error) {
+			var cfg MeanReversionConfig
+			if err := json.Unmarshal(params, &cfg); err != nil {
+				return nil, err
+			}
+			return NewMeanReversionStrategy(&cfg)
+		},
+	)
+}
+
+// MeanReversionStrategy входит против отклонения цены более чем на entryZ
+// стандартных отклонений и выходит при возврате к средней или по стопу.
+// В режиме zscore сигналом служит z-оценка последнего значения arg,
+// в режиме bollinger - положение цены закрытия относительно полос шириной entryZ по arg.
+type MeanReversionStrategy struct {
+	symbol      string
+	interval    cdl.Interval
+	arg         cdl.CandleArg
+	bands       string
+	period      int
+	entryZ      float64
+	exitZ       float64
+	stopZ       float64
+	stopLoss    float64
+	orderAmt    float64
+	allowShort  bool
+	closeOnStop bool
+
+	qtyPrecision int
+	minOrderAmt  float64
+	isSpot       bool
+
+	ctx              context.Context
+	subData          *trading.SubData
+	orderRequestChan chan<- *trading.OrderRequest
+
+	candleStreamChan chan *cdl.CandleStreamData
+	candleStream     chan<- struct{}
+	orderUpdateChan  chan *trading.OrderUpdate
+	done             chan struct{}
+
+	ledger *trading.PositionLedger
+
+	pending bool    // Ордер отправлен и еще не закрыт
+	feeQty  float64 // Комиссии, списанные в базовой монете (спот)
+	mu      sync.Mutex
+
+	isWorking atomic.Bool
+}
+
+func NewMeanReversionStrategy(cfg *MeanReversionConfig) (*MeanReversionStrategy, error) {
+	if cfg.Symbol == "" {
+		return nil, fmt.Errorf("symbol not specified in configuration parameters")
+	}
+	interval, err := cdl.ParseInterval(cfg.Interval)
+	if err != nil {
+		return nil, err
+	}
+	arg := cdl.Close
+	if cfg.Arg != "" {
+		if arg, err = cdl.ParseCandleArg(cfg.Arg); err != nil {
+			return nil, err
+		}
+	}
+	bands := cfg.Bands
+	switch bands {
+	case "":
+		bands = BandsZScore
+	case BandsZScore, BandsBollinger:
+	default:
+		return nil, fmt.Errorf("unknown bands type: %s", bands)
+	}
+	if cfg.Period < 2 {
+		return nil, fmt.Errorf("period must be at least 2: %d", cfg.Period)
+	}
+	if cfg.OrderAmt <= 0 {
+		return nil, fmt.Errorf("order amt must be positive: %f", cfg.OrderAmt)
+	}
+
+	entryZ := 2.
+	if cfg.EntryZ != nil {
+		entryZ = *cfg.EntryZ
+	}
+	var exitZ float64
+	if cfg.ExitZ != nil {
+		exitZ = min(*cfg.ExitZ, entryZ)
+	}
+	var stopZ float64
+	if bands == BandsBollinger && entryZ <= 0 {
+		return nil, fmt.Errorf("entryZ must be positive for bollinger bands: %f", entryZ)
+	}
+	if cfg.StopZ != nil {
+		stopZ = *cfg.StopZ
+		if stopZ <= entryZ {
+			return nil, fmt.Errorf("stopZ must be greater than entryZ: %f <= %f", stopZ, entryZ)
+		}
+	}
+	var stopLoss float64
+	if cfg.StopLoss != nil {
+		stopLoss = *cfg.StopLoss
+	}
+	allowShort := true
+	if cfg.AllowShort != nil {
+		allowShort = *cfg.AllowShort
+	}
+	closeOnStop := true
+	if cfg.CloseOnStop != nil {
+		closeOnStop = *cfg.CloseOnStop
+	}
+
+	s := &MeanReversionStrategy{
+		symbol:      cfg.Symbol,
+		interval:    interval,
+		arg:         arg,
+		bands:       bands,
+		period:      cfg.Period,
+		entryZ:      entryZ,
+		exitZ:       exitZ,
+		stopZ:       stopZ,
+		stopLoss:    stopLoss,
+		orderAmt:    cfg.OrderAmt,
+		allowShort:  allowShort,
+		closeOnStop: closeOnStop,
+	}
+
+	return s, nil
+}
+
+func (s *MeanReversionStrategy) Init(ctx context.Context, subData *trading.SubData, req chan<- *trading.OrderRequest) {
+	s.ctx = ctx
+	s.subData = subData
+	s.orderRequestChan = req
+
+	go func() {
+		<-s.ctx.Done()
+		s.Stop()
+	}()
+}
+
+func (s *MeanReversionStrategy) Launch() (err error) {
+	if !s.isWorking.CompareAndSwap(false, true) {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			s.isWorking.Store(false)
+		}
+	}()
+
+	instrumentInfo, err := s.subData.GetInstrumentInfo(s.symbol)
+	if err != nil {
+		return err
+	}
+	s.qtyPrecision = instrumentInfo.QtyPrecision
+	s.minOrderAmt = instrumentInfo.MinOrderAmt
+	s.isSpot = instrumentInfo.Category == "spot"
+	if s.isSpot {
+		s.allowShort = false
+	}
+	if s.orderAmt < s.minOrderAmt {
+		err = fmt.Errorf(
+			"order amt is less than the minimum order amt: %f < %f",
+			s.orderAmt,
+			s.minOrderAmt,
+		)
+		return err
+	}
+
+	candles, err := s.readConfirmCandles()
+	if err != nil {
+		return err
+	}
+	if len(candles) < s.period {
+		err = fmt.Errorf("not enough candles to launch: %d < %d", len(candles), s.period)
+		return err
+	}
+
+	s.candleStreamChan = make(chan *cdl.CandleStreamData)
+	done, err := s.subData.SubscribeChan(s.symbol, s.interval, s.candleStreamChan)
+	if err != nil {
+		close(s.candleStreamChan)
+		return err
+	}
+	s.candleStream = done
+	s.orderUpdateChan = make(chan *trading.OrderUpdate)
+	s.done = make(chan struct{})
+	s.ledger = trading.NewPositionLedger(s.qtyPrecision)
+	s.mu.Lock()
+	s.pending = false
+	s.feeQty = 0
+	s.mu.Unlock()
+
+	go s.orderUpdate()
+	go s.observe()
+
+	return nil
+}
+
+// Symbols возвращает символ стратегии
+func (s *MeanReversionStrategy) Symbols() []string {
+	return []string{s.symbol}
+}
+
+func (s *MeanReversionStrategy) Stop() bool {
+	if !s.isWorking.CompareAndSwap(true, false) {
+		return false
+	}
+
+	close(s.candleStream)
+	if s.closeOnStop {
+		if qty := s.positionQty(); qty != 0 {
+			s.orderRequestChan <- trading.NewOrderRequest(
+				trading.NewOrder(s.symbol, -qty, nil),
+			)
+		}
+	}
+	close(s.done)
+
+	return true
+}
+
+// Position возвращает текущее состояние позиции стратегии
+func (s *MeanReversionStrategy) Position() trading.Position {
+	return s.ledger.Position()
+}
+
+func (s *MeanReversionStrategy) readConfirmCandles() ([]cdl.Candle, error) {
+	return s.subData.ReadConfirmCandles(s.symbol, s.interval, s.period)
+}
+
+// positionQty возвращает размер позиции за вычетом комиссий, списанных в базовой монете
+func (s *MeanReversionStrategy) positionQty() float64 {
+	s.mu.Lock()
+	feeQty := s.feeQty
+	s.mu.Unlock()
+
+	return numeric.TruncateFloat(s.ledger.Qty()-feeQty, s.qtyPrecision)
+}
+
+// zScore возвращает отклонение сигнальной цены от средней по arg в стандартных отклонениях.
+// В режиме bollinger отклонение цены закрытия выражается через полосы шириной entryZ,
+// так что выход за полосу соответствует z-оценке за пределами ±entryZ.
+func (s *MeanReversionStrategy) zScore(candles []cdl.Candle) float64 {
+	values := cdl.ListOfCandleArg(candles, s.arg)
+	if s.bands == BandsBollinger {
+		mid, upper, _ := norm.BollingerBands(values, s.period, s.entryZ)
+		if width := upper - mid; width != 0 {
+			return s.entryZ * (candles[len(candles)-1].C - mid) / width
+		}
+		return 0
+	}
+	return norm.ZScore(values[max(0, len(values)-s.period):])
+}
+
+func (s *MeanReversionStrategy) observe() {
+	for data := range s.candleStreamChan {
+		if data.Confirm {
+			s.confirmHandler()
+		} else {
+			s.checkStopLoss(data.Candle.C)
+		}
+	}
+}
+
+func (s *MeanReversionStrategy) confirmHandler() {
+	candles, err := s.readConfirmCandles()
+	if err != nil || len(candles) < s.period {
+		log.Printf("get confirm candles error: %v", err)
+		return
+	}
+	price := candles[len(candles)-1].C
+	if s.checkStopLoss(price) {
+		return
+	}
+
+	z := s.zScore(candles)
+	qty := s.positionQty()
+	switch {
+	case qty > 0:
+		if z >= -s.exitZ || (s.stopZ > 0 && z <= -s.stopZ) {
+			s.sendOrder(-qty)
+		}
+	case qty < 0:
+		if z <= s.exitZ || (s.stopZ > 0 && z >= s.stopZ) {
+			s.sendOrder(-qty)
+		}
+	case z <= -s.entryZ && (s.stopZ == 0 || z > -s.stopZ):
+		s.sendOrder(numeric.TruncateFloat(s.orderAmt/price, s.qtyPrecision))
+	case z >= s.entryZ && (s.stopZ == 0 || z < s.stopZ) && s.allowShort:
+		s.sendOrder(-numeric.TruncateFloat(s.orderAmt/price, s.qtyPrecision))
+	}
+}
+
+// checkStopLoss закрывает позицию, если цена ушла от средней цены входа дальше stopLoss
+func (s *MeanReversionStrategy) checkStopLoss(price float64) bool {
+	if s.stopLoss <= 0 {
+		return false
+	}
+	position := s.ledger.Position()
+	qty := s.positionQty()
+	if qty == 0 {
+		return false
+	}
+	loss := (position.AvgPrice - price) / position.AvgPrice
+	if qty < 0 {
+		loss = -loss
+	}
+	if loss < s.stopLoss {
+		return false
+	}
+	s.sendOrder(-qty)
+
+	return true
+}
+
+func (s *MeanReversionStrategy) sendOrder(qty float64) {
+	if qty == 0 || !s.isWorking.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	if s.pending {
+		s.mu.Unlock()
+		return
+	}
+	s.pending = true
+	s.mu.Unlock()
+
+	s.orderRequestChan <- trading.NewOrderRequest(
+		trading.NewOrder(s.symbol, qty, nil),
+		trading.WithLinkId(uuid.NewString()),
+		trading.WithTag(MeanReversionStrategyType),
+		trading.WithReply(s.orderUpdateChan),
+	)
+}
+
+func (s *MeanReversionStrategy) orderUpdate() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case update := <-s.orderUpdateChan:
+			if update.Error != "" {
+				s.mu.Lock()
+				s.pending = false
+				s.mu.Unlock()
+				continue
+			}
+			if update.Order.ID == "" {
+				continue
+			}
+			fill, _, ok := s.ledger.ApplyOrder(update.LinkId, update.Order)
+			if ok && s.isSpot && fill.Qty > 0 && fill.Price > 0 {
+				s.mu.Lock()
+				s.feeQty += fill.Fee / fill.Price
+				s.mu.Unlock()
+			}
+			if update.Order.IsClosed {
+				s.mu.Lock()
+				s.pending = false
+				s.mu.Unlock()
+				if p := s.ledger.Position(); s.positionQty() == 0 {
+					log.Printf("%s position closed: %+v", s.symbol, p)
+				}
+			}
+		}
+	}
+}
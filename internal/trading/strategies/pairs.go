@@ -0,0 +1,518 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/utils/norm"
+	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
+)
+
+const (
+	PairsStrategyType = "pairs"
+
+	pairsTagEntry  = "pairs-entry"
+	pairsTagExit   = "pairs-exit"
+	pairsTagUnwind = "pairs-unwind"
+)
+
+type PairsConfig struct {
+	SymbolA     string   `json:"symbolA"`
+	SymbolB     string   `json:"symbolB"`
+	Interval    string   `json:"interval"`
+	Lookback    int      `json:"lookback"`
+	EntryZ      *float64 `json:"entryZ"`
+	ExitZ       *float64 `json:"exitZ"`
+	StopZ       *float64 `json:"stopZ"`
+	LegAmt      float64  `json:"legAmt"`
+	CloseOnStop *bool    `json:"closeOnStop"`
+}
+
+var pairsConfigSchema = trading.ConfigSchema{
+	trading.NewParam("symbolA", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("symbolB", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("interval", trading.ParamInterval, trading.IsRequired()),
+	trading.NewParam("lookback", trading.ParamInteger, trading.IsRequired(), trading.WithMin(10), trading.WithMax(1000)),
+	trading.NewParam("entryZ", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("exitZ", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("stopZ", trading.ParamNumber, trading.WithMin(0)),
+	trading.NewParam("legAmt", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0),
+		trading.WithDescription("quote amount of the symbolA leg, symbolB leg is sized by the hedge ratio")),
+	trading.NewParam("closeOnStop", trading.ParamBool),
+}
+
+func init() {
+	trading.RegisterStrategy(
+		PairsStrategyType,
+		pairsConfigSchema,
+		func(params json.RawMessage) (trading.Strategy, error) {
+			var cfg PairsConfig
+			if err := json.Unmarshal(params, &cfg); err != nil {
+				return nil, err
+			}
+			return NewPairsStrategy(&cfg)
+		},
+	)
+}
+
+// pairLeg - инструмент одной ноги пары
+type pairLeg struct {
+	symbol       string
+	qtyPrecision int
+	minOrderAmt  float64
+	ledger       *trading.PositionLedger
+	lastConfirm  int64
+	candleChan   chan *cdl.CandleStreamData
+	candleStream chan<- struct{}
+}
+
+// pairExec - состояние парного исполнения двух ног
+type pairExec struct {
+	id        string
+	tag       string
+	resolved  [2]bool
+	execQty   [2]float64
+	errs      [2]string
+	startedAt time.Time
+}
+
+// PairsStrategy торгует спред log(A) - beta*log(B) двух инструментов,
+// где beta - скользящий коэффициент хеджирования по методу наименьших квадратов.
+// Обе ноги открываются и закрываются вместе; если одна нога не исполнилась,
+// исполненная нога закрывается рыночным ордером.
+type PairsStrategy struct {
+	interval    cdl.Interval
+	lookback    int
+	entryZ      float64
+	exitZ       float64
+	stopZ       float64
+	legAmt      float64
+	closeOnStop bool
+
+	legs [2]*pairLeg
+
+	ctx              context.Context
+	subData          *trading.SubData
+	orderRequestChan chan<- *trading.OrderRequest
+	orderUpdateChan  chan *trading.OrderUpdate
+	done             chan struct{}
+
+	side     int // 1 - длинный спред (long A, short B), -1 - короткий, 0 - нет позиции
+	lastEval int64
+	pending  *pairExec
+	mu       sync.Mutex
+
+	isWorking atomic.Bool
+}
+
+func NewPairsStrategy(cfg *PairsConfig) (*PairsStrategy, error) {
+	if cfg.SymbolA == "" || cfg.SymbolB == "" {
+		return nil, fmt.Errorf("both symbols must be specified in configuration parameters")
+	}
+	if cfg.SymbolA == cfg.SymbolB {
+		return nil, fmt.Errorf("pair symbols must differ: %s", cfg.SymbolA)
+	}
+	interval, err := cdl.ParseInterval(cfg.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Lookback < 10 {
+		return nil, fmt.Errorf("lookback must be at least 10: %d", cfg.Lookback)
+	}
+	if cfg.LegAmt <= 0 {
+		return nil, fmt.Errorf("leg amt must be positive: %f", cfg.LegAmt)
+	}
+
+	entryZ := 2.
+	if cfg.EntryZ != nil {
+		entryZ = *cfg.EntryZ
+	}
+	exitZ := .5
+	if cfg.ExitZ != nil {
+		exitZ = min(*cfg.ExitZ, entryZ)
+	}
+	var stopZ float64
+	if cfg.StopZ != nil {
+		stopZ = *cfg.StopZ
+		if stopZ <= entryZ {
+			return nil, fmt.Errorf("stopZ must be greater than entryZ: %f <= %f", stopZ, entryZ)
+		}
+	}
+	closeOnStop := true
+	if cfg.CloseOnStop != nil {
+		closeOnStop = *cfg.CloseOnStop
+	}
+
+	s := &PairsStrategy{
+		interval:    interval,
+		lookback:    cfg.Lookback,
+		entryZ:      entryZ,
+		exitZ:       exitZ,
+		stopZ:       stopZ,
+		legAmt:      cfg.LegAmt,
+		closeOnStop: closeOnStop,
+		legs: [2]*pairLeg{
+			{symbol: cfg.SymbolA},
+			{symbol: cfg.SymbolB},
+		},
+	}
+
+	return s, nil
+}
+
+func (s *PairsStrategy) Init(ctx context.Context, subData *trading.SubData, req chan<- *trading.OrderRequest) {
+	s.ctx = ctx
+	s.subData = subData
+	s.orderRequestChan = req
+
+	go func() {
+		<-s.ctx.Done()
+		s.Stop()
+	}()
+}
+
+func (s *PairsStrategy) Launch() (err error) {
+	if !s.isWorking.CompareAndSwap(false, true) {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			s.isWorking.Store(false)
+		}
+	}()
+
+	for _, leg := range s.legs {
+		instrumentInfo, err := s.subData.GetInstrumentInfo(leg.symbol)
+		if err != nil {
+			return err
+		}
+		if instrumentInfo.Category == "spot" {
+			err = fmt.Errorf("pairs strategy requires short selling, spot is not supported: %s", leg.symbol)
+			return err
+		}
+		leg.qtyPrecision = instrumentInfo.QtyPrecision
+		leg.minOrderAmt = instrumentInfo.MinOrderAmt
+		leg.ledger = trading.NewPositionLedger(leg.qtyPrecision)
+		leg.lastConfirm = 0
+	}
+	if _, _, err = s.alignedCloses(); err != nil {
+		return err
+	}
+
+	for i, leg := range s.legs {
+		leg.candleChan = make(chan *cdl.CandleStreamData)
+		done, err := s.subData.SubscribeChan(leg.symbol, s.interval, leg.candleChan)
+		if err != nil {
+			close(leg.candleChan)
+			for _, prev := range s.legs[:i] {
+				close(prev.candleStream)
+			}
+			return err
+		}
+		leg.candleStream = done
+	}
+	s.orderUpdateChan = make(chan *trading.OrderUpdate)
+	s.done = make(chan struct{})
+
+	s.mu.Lock()
+	s.side = 0
+	s.pending = nil
+	s.mu.Unlock()
+
+	go s.orderUpdate()
+	for i := range s.legs {
+		go s.observe(i)
+	}
+
+	return nil
+}
+
+// Symbols возвращает символы обеих ног пары
+func (s *PairsStrategy) Symbols() []string {
+	return []string{s.legs[0].symbol, s.legs[1].symbol}
+}
+
+func (s *PairsStrategy) Stop() bool {
+	if !s.isWorking.CompareAndSwap(true, false) {
+		return false
+	}
+
+	for _, leg := range s.legs {
+		close(leg.candleStream)
+		if !s.closeOnStop {
+			continue
+		}
+		if qty := leg.ledger.Qty(); qty != 0 {
+			s.orderRequestChan <- trading.NewOrderRequest(
+				trading.NewOrder(leg.symbol, -qty, nil),
+			)
+		}
+	}
+	close(s.done)
+
+	return true
+}
+
+// Positions возвращает текущее состояние позиций обеих ног
+func (s *PairsStrategy) Positions() [2]trading.Position {
+	return [2]trading.Position{
+		s.legs[0].ledger.Position(),
+		s.legs[1].ledger.Position(),
+	}
+}
+
+// observe отслеживает подтвержденные свечи ноги и запускает расчет,
+// когда обе ноги закрыли свечу с одинаковым временем
+func (s *PairsStrategy) observe(i int) {
+	for data := range s.legs[i].candleChan {
+		if !data.Confirm {
+			continue
+		}
+		s.mu.Lock()
+		s.legs[i].lastConfirm = data.Candle.Time
+		t := s.legs[0].lastConfirm
+		ready := t == s.legs[1].lastConfirm && t != s.lastEval
+		if ready {
+			s.lastEval = t
+		}
+		s.mu.Unlock()
+
+		if ready {
+			s.evaluate()
+		}
+	}
+}
+
+// alignedCloses возвращает цены закрытия обеих ног, выровненные по времени свечей
+func (s *PairsStrategy) alignedCloses() ([]float64, []float64, error) {
+	candlesA, err := s.subData.ReadConfirmCandles(s.legs[0].symbol, s.interval, s.lookback*2)
+	if err != nil {
+		return nil, nil, err
+	}
+	candlesB, err := s.subData.ReadConfirmCandles(s.legs[1].symbol, s.interval, s.lookback*2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closesB := make(map[int64]float64, len(candlesB))
+	for _, c := range candlesB {
+		closesB[c.Time] = c.C
+	}
+	a := make([]float64, 0, len(candlesA))
+	b := make([]float64, 0, len(candlesA))
+	for _, c := range candlesA {
+		if cb, ok := closesB[c.Time]; ok {
+			a = append(a, c.C)
+			b = append(b, cb)
+		}
+	}
+	if len(a) < s.lookback {
+		return nil, nil, fmt.Errorf("not enough aligned candles: %d < %d", len(a), s.lookback)
+	}
+
+	return a[len(a)-s.lookback:], b[len(b)-s.lookback:], nil
+}
+
+// hedgeRatio возвращает коэффициент beta и z-оценку последнего значения спреда
+func hedgeRatio(a, b []float64) (float64, float64) {
+	la := make([]float64, len(a))
+	lb := make([]float64, len(b))
+	for i := range a {
+		la[i] = math.Log(a[i])
+		lb[i] = math.Log(b[i])
+	}
+	meanA, meanB := numeric.Avg(la), numeric.Avg(lb)
+	var cov, varB float64
+	for i := range la {
+		cov += (la[i] - meanA) * (lb[i] - meanB)
+		varB += (lb[i] - meanB) * (lb[i] - meanB)
+	}
+	if varB == 0 {
+		return 0, 0
+	}
+	beta := cov / varB
+
+	spread := make([]float64, len(la))
+	for i := range la {
+		spread[i] = la[i] - beta*lb[i]
+	}
+
+	return beta, norm.ZScore(spread)
+}
+
+func (s *PairsStrategy) evaluate() {
+	a, b, err := s.alignedCloses()
+	if err != nil {
+		log.Printf("pairs %s/%s: %s", s.legs[0].symbol, s.legs[1].symbol, err)
+		return
+	}
+	beta, z := hedgeRatio(a, b)
+
+	s.mu.Lock()
+	if s.pending != nil {
+		if time.Since(s.pending.startedAt) < 3*time.Minute {
+			s.mu.Unlock()
+			return
+		}
+		log.Printf("pairs execution %s timed out: %+v", s.pending.id, s.pending)
+		s.pending = nil
+	}
+	side := s.side
+	s.mu.Unlock()
+
+	priceA, priceB := a[len(a)-1], b[len(b)-1]
+	switch {
+	case side != 0:
+		exit := math.Abs(z) <= s.exitZ || (s.stopZ > 0 && math.Abs(z) >= s.stopZ) ||
+			(side > 0 && z >= 0) || (side < 0 && z <= 0)
+		if exit {
+			s.sendPair(pairsTagExit, -s.legs[0].ledger.Qty(), -s.legs[1].ledger.Qty())
+		}
+	case beta <= 0:
+	case math.Abs(z) >= s.entryZ && (s.stopZ == 0 || math.Abs(z) < s.stopZ):
+		dir := 1.
+		if z > 0 {
+			// Спред выше средней: продаем A, покупаем B
+			dir = -1
+		}
+		qtyA := numeric.TruncateFloat(s.legAmt/priceA, s.legs[0].qtyPrecision)
+		qtyB := numeric.TruncateFloat(beta*s.legAmt/priceB, s.legs[1].qtyPrecision)
+		if qtyA*priceA < s.legs[0].minOrderAmt || qtyB*priceB < s.legs[1].minOrderAmt {
+			log.Printf("pairs leg qty less than minimum limit: %f, %f", qtyA*priceA, qtyB*priceB)
+			return
+		}
+		s.sendPair(pairsTagEntry, dir*qtyA, -dir*qtyB)
+	}
+}
+
+// sendPair отправляет ордера обеих ног как одно парное исполнение
+func (s *PairsStrategy) sendPair(tag string, qtyA, qtyB float64) {
+	if !s.isWorking.Load() {
+		return
+	}
+
+	qtys := [2]float64{qtyA, qtyB}
+	exec := newPairExec(tag, qtys)
+
+	s.mu.Lock()
+	if s.pending != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.pending = exec
+	s.mu.Unlock()
+
+	s.sendLegs(exec, qtys)
+}
+
+// newPairExec создает парное исполнение; ноги с нулевым количеством считаются завершенными
+func newPairExec(tag string, qtys [2]float64) *pairExec {
+	exec := &pairExec{id: uuid.NewString(), tag: tag, startedAt: time.Now()}
+	for i, qty := range qtys {
+		if qty == 0 {
+			exec.resolved[i] = true
+		}
+	}
+	return exec
+}
+
+// sendLegs отправляет ордера ног парного исполнения
+func (s *PairsStrategy) sendLegs(exec *pairExec, qtys [2]float64) {
+	for i, qty := range qtys {
+		if qty == 0 {
+			continue
+		}
+		s.orderRequestChan <- trading.NewOrderRequest(
+			trading.NewOrder(s.legs[i].symbol, qty, nil),
+			trading.WithLinkId(fmt.Sprintf("%s:%d", exec.id, i)),
+			trading.WithTag(exec.tag),
+			trading.WithReply(s.orderUpdateChan),
+		)
+	}
+}
+
+func (s *PairsStrategy) orderUpdate() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case update := <-s.orderUpdateChan:
+			s.handleOrderUpdate(update)
+		}
+	}
+}
+
+func (s *PairsStrategy) handleOrderUpdate(update *trading.OrderUpdate) {
+	id, legStr, ok := strings.Cut(update.LinkId, ":")
+	if !ok {
+		return
+	}
+	i, err := strconv.Atoi(legStr)
+	if err != nil || i < 0 || i > 1 {
+		return
+	}
+	if update.Order.ID != "" {
+		s.legs[i].ledger.ApplyOrder(update.LinkId, update.Order)
+	}
+
+	s.mu.Lock()
+	exec := s.pending
+	if exec == nil || exec.id != id {
+		s.mu.Unlock()
+		return
+	}
+	switch {
+	case update.Error != "":
+		exec.resolved[i] = true
+		exec.errs[i] = update.Error
+	case update.Order.IsClosed:
+		exec.resolved[i] = true
+		exec.execQty[i] = update.Order.ExecQty
+	}
+	if !exec.resolved[0] || !exec.resolved[1] {
+		s.mu.Unlock()
+		return
+	}
+	s.pending = nil
+	s.finishPair(exec)
+	s.mu.Unlock()
+}
+
+// finishPair обрабатывает завершенное парное исполнение. Вызывается под s.mu.
+func (s *PairsStrategy) finishPair(exec *pairExec) {
+	qtyA, qtyB := s.legs[0].ledger.Qty(), s.legs[1].ledger.Qty()
+
+	if (qtyA == 0) != (qtyB == 0) {
+		// Открыта только одна нога: позиция не захеджирована, закрываем ее.
+		// Пара остается занятой, пока закрытие не завершится.
+		log.Printf("pairs %s %s left a single open leg, unwinding: %v", exec.tag, exec.id, exec.errs)
+		if !s.isWorking.Load() {
+			return
+		}
+		qtys := [2]float64{-qtyA, -qtyB}
+		s.pending = newPairExec(pairsTagUnwind, qtys)
+		go s.sendLegs(s.pending, qtys)
+		return
+	}
+
+	switch {
+	case qtyA == 0:
+		s.side = 0
+	case qtyA > 0:
+		s.side = 1
+	default:
+		s.side = -1
+	}
+}
@@ -2,6 +2,7 @@ package strategies
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
@@ -13,9 +14,50 @@ import (
 	"github.com/nikita55612/goTradingBot/internal/trading"
 	"github.com/nikita55612/goTradingBot/internal/trading/predict"
 	"github.com/nikita55612/goTradingBot/internal/utils/numeric"
-	"github.com/nikita55612/goTradingBot/internal/utils/seqs"
 )
 
+const TrendStrategyType = "trend"
+
+type TrendConfig struct {
+	Symbol           string    `json:"symbol"`
+	Interval         string    `json:"interval"`
+	AvailableBalance float64   `json:"availableBalance"`
+	LongRatio        *float64  `json:"longRatio"`
+	MartngaleRatios  []float64 `json:"martngaleRatios"`
+	TrendZoneFilter  *float64  `json:"trendZoneFilter"`
+	LimitOrderOffset *float64  `json:"limitOrderOffset"`
+	MaxFundingRate   *float64  `json:"maxFundingRate"`
+	FundingWindow    *int      `json:"fundingWindow"`
+}
+
+var trendConfigSchema = trading.ConfigSchema{
+	trading.NewParam("symbol", trading.ParamString, trading.IsRequired()),
+	trading.NewParam("interval", trading.ParamInterval, trading.IsRequired()),
+	trading.NewParam("availableBalance", trading.ParamNumber, trading.IsRequired(), trading.WithMin(0)),
+	trading.NewParam("longRatio", trading.ParamNumber, trading.WithMin(0), trading.WithMax(1)),
+	trading.NewParam("martngaleRatios", trading.ParamNumberList, trading.WithMin(0)),
+	trading.NewParam("trendZoneFilter", trading.ParamNumber, trading.WithMin(0), trading.WithMax(.7)),
+	trading.NewParam("limitOrderOffset", trading.ParamNumber, trading.WithMin(0), trading.WithMax(.1)),
+	trading.NewParam("maxFundingRate", trading.ParamNumber,
+		trading.WithDescription("do not hold a position through funding costing more than this rate")),
+	trading.NewParam("fundingWindow", trading.ParamInteger, trading.WithMin(1),
+		trading.WithDescription("minutes before funding settlement when maxFundingRate applies, default 30")),
+}
+
+func init() {
+	trading.RegisterStrategy(
+		TrendStrategyType,
+		trendConfigSchema,
+		func(params json.RawMessage) (trading.Strategy, error) {
+			var cfg TrendConfig
+			if err := json.Unmarshal(params, &cfg); err != nil {
+				return nil, err
+			}
+			return NewTrendStrategy(&cfg)
+		},
+	)
+}
+
 type TrendStrategy struct {
 	symbol   string
 	interval cdl.Interval
@@ -39,10 +81,12 @@ type TrendStrategy struct {
 	availableBalance float64
 	longRatio        float64
 
-	// _PnL            float64
-	orderLog         *seqs.OrderedMap[string, *trading.Order]
-	qtyPosition      atomic.Pointer[float64]
-	avgPositionPrice atomic.Pointer[float64]
+	ledger *trading.PositionLedger
+
+	maxFundingRate *float64
+	fundingWindow  time.Duration
+	fundingGuard   *trading.FundingGuard
+	stopFunding    context.CancelFunc
 
 	trendPredictor  *predict.TrendPredictor
 	trendZoneFilter float64
@@ -61,7 +105,7 @@ type TrendStrategy struct {
 	isWorking            atomic.Bool
 }
 
-func NewTrendStrategy(cfg *trading.StrategyConfig) (*TrendStrategy, error) {
+func NewTrendStrategy(cfg *TrendConfig) (*TrendStrategy, error) {
 	if cfg.Symbol == "" {
 		return nil, fmt.Errorf("symbol not specified in configuration parameters")
 	}
@@ -132,7 +176,14 @@ func NewTrendStrategy(cfg *trading.StrategyConfig) (*TrendStrategy, error) {
 		}
 	}
 
+	fundingWindow := 30 * time.Minute
+	if cfg.FundingWindow != nil {
+		fundingWindow = time.Duration(*cfg.FundingWindow) * time.Minute
+	}
+
 	s := &TrendStrategy{
+		maxFundingRate:   cfg.MaxFundingRate,
+		fundingWindow:    fundingWindow,
 		symbol:           cfg.Symbol,
 		interval:         interval,
 		availableBalance: cfg.AvailableBalance,
@@ -243,11 +294,12 @@ func (s *TrendStrategy) Launch() (err error) {
 	s.confirmHandlerChan = make(chan *cdl.Candle)
 	s.backgroundChan = make(chan *cdl.Candle)
 
-	s.orderLog = seqs.NewOrderedMap[string, *trading.Order](100)
-	qtyPosition := 0.
-	s.qtyPosition.Store(&qtyPosition)
-	avgPricePosition := 0.
-	s.avgPositionPrice.Store(&avgPricePosition)
+	s.ledger = trading.NewPositionLedger(s.qtyPrecision)
+	if s.maxFundingRate != nil && instrumentInfo.Category != "spot" {
+		s.fundingGuard = trading.NewFundingGuard(
+			s.subData, s.symbol, s.ledger, *s.maxFundingRate, s.fundingWindow,
+		)
+	}
 
 	candles, err := s.readConfirmCandles(predict.TpIBS)
 	if err != nil {
@@ -259,6 +311,12 @@ func (s *TrendStrategy) Launch() (err error) {
 		return err
 	}
 
+	if s.fundingGuard != nil {
+		var fundingCtx context.Context
+		fundingCtx, s.stopFunding = context.WithCancel(s.ctx)
+		go s.fundingGuard.Run(fundingCtx)
+	}
+
 	go s.orderUpdate()
 	go s.background()
 	go s.confirmHandler()
@@ -267,19 +325,27 @@ func (s *TrendStrategy) Launch() (err error) {
 	return nil
 }
 
+// Symbols возвращает символ стратегии
+func (s *TrendStrategy) Symbols() []string {
+	return []string{s.symbol}
+}
+
 func (s *TrendStrategy) Stop() bool {
 	if !s.isWorking.CompareAndSwap(true, false) {
 		return false
 	}
 
 	close(s.confirmHandlerChan)
+	if s.stopFunding != nil {
+		s.stopFunding()
+	}
 
 	timeNow := time.Now().UnixMilli()
 	if timeNow-s.lastOrderRequestTime < 500 {
 		time.Sleep(300 * time.Millisecond)
 	}
 
-	qtyPosition := *s.qtyPosition.Load()
+	qtyPosition := s.ledger.Qty()
 	if qtyPosition != 0 {
 		s.orderRequestChan <- trading.NewOrderRequest(
 			trading.NewOrder(s.symbol, -qtyPosition, nil),
@@ -299,56 +365,27 @@ func (s *TrendStrategy) orderUpdate() {
 			continue
 		}
 
-		execQty := update.Order.ExecQty
-		if execQty == 0 {
+		prevQtyPosition := s.ledger.Qty()
+		_, realized, ok := s.ledger.ApplyOrder(update.LinkId, update.Order)
+		if !ok {
 			continue
 		}
+		qtyPosition := s.ledger.Qty()
 
-		if o, ok := s.orderLog.Get(update.LinkId); ok {
-			execQty -= o.ExecQty
-		}
-
-		prevQtyPosition := *s.qtyPosition.Load()
-		qtyPosition := prevQtyPosition + execQty
-		qtyPosition = numeric.TruncateFloat(qtyPosition, s.qtyPrecision)
-		s.qtyPosition.Store(&qtyPosition)
-
-		s.orderLog.Set(update.LinkId, update.Order)
-
-		if s.orderLog.Len() == 1 {
-			s.avgPositionPrice.Store(&update.Order.AvgPrice)
-			continue
-		}
-		if (prevQtyPosition > 0) == (qtyPosition > 0) && qtyPosition != 0 {
-			avgPrice := *s.avgPositionPrice.Load()
-			newAvgPrice := (avgPrice + update.Order.AvgPrice) / 2
-			newAvgPrice = numeric.TruncateFloat(newAvgPrice, s.tickSizePrecision)
-			s.avgPositionPrice.Store(&newAvgPrice)
+		// Серия убытков учитывается только при закрытии или перевороте позиции
+		if prevQtyPosition == 0 || (prevQtyPosition > 0) == (qtyPosition > 0) && qtyPosition != 0 {
 			continue
 		}
 
-		prevAvgPrice := *s.avgPositionPrice.Load()
-		s.avgPositionPrice.Store(&update.Order.AvgPrice)
-
+		losses := &s.shortLosses
 		if prevQtyPosition > 0 {
-			if prevAvgPrice > update.Order.AvgPrice {
-				v := *s.longLosses.Load() + 1
-				s.longLosses.Store(&v)
-			} else {
-				v := 0
-				s.longLosses.Store(&v)
-			}
+			losses = &s.longLosses
 		}
-
-		if prevQtyPosition < 0 {
-			if prevAvgPrice < update.Order.AvgPrice {
-				v := *s.shortLosses.Load() + 1
-				s.shortLosses.Store(&v)
-			} else {
-				v := 0
-				s.shortLosses.Store(&v)
-			}
+		v := 0
+		if realized < 0 {
+			v = *losses.Load() + 1
 		}
+		losses.Store(&v)
 	}
 }
 
@@ -407,7 +444,8 @@ func (s *TrendStrategy) confirmHandler() {
 			continue
 		}
 
-		if p[1] == 0 {
+		// Позиция, которую нежелательно держать через расчет фандинга, закрывается и без прогноза
+		if p[1] == 0 && (s.fundingGuard == nil || !s.fundingGuard.ShouldAvoid(s.ledger.Qty())) {
 			continue
 		}
 
@@ -424,7 +462,11 @@ func (s *TrendStrategy) confirmHandler() {
 			}
 		}
 
-		qtyPosition := *s.qtyPosition.Load()
+		if s.fundingGuard != nil && s.fundingGuard.ShouldAvoid(directedQty) {
+			directedQty = 0
+		}
+
+		qtyPosition := s.ledger.Qty()
 
 		if qtyPosition == 0 && directedQty == 0 {
 			continue
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/nikita55612/goTradingBot/internal/pkg/book"
 	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/tick"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ticker"
 )
 
 type DataProvider interface {
@@ -18,11 +21,15 @@ type InstrumentInfo struct {
 	QtyPrecision int     `json:"qtyPrecision"`
 	MinOrderAmt  float64 `json:"minOrderAmt"`
 	TickSize     float64 `json:"tickSize"`
+	Category     string  `json:"category"`
 }
 
 type SubData struct {
 	dataProvider DataProvider
 	candleSyncs  map[string]*cdl.CandleSync
+	orderBooks   map[string]*book.OrderBook
+	tickSyncs    map[string]*tick.Sync
+	tickerSyncs  map[string]*ticker.Sync
 	bufferSize   int
 	ctx          context.Context
 	mu           sync.Mutex
@@ -32,6 +39,9 @@ func NewSubData(ctx context.Context, dataProvider DataProvider, bufferSize int)
 	return &SubData{
 		dataProvider: dataProvider,
 		candleSyncs:  make(map[string]*cdl.CandleSync),
+		orderBooks:   make(map[string]*book.OrderBook),
+		tickSyncs:    make(map[string]*tick.Sync),
+		tickerSyncs:  make(map[string]*ticker.Sync),
 		bufferSize:   bufferSize,
 		ctx:          ctx,
 	}
@@ -41,11 +51,27 @@ func (s *SubData) getCandleSync(symbol string, interval cdl.Interval) (*cdl.Cand
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.candleSyncLocked(symbol, interval)
+}
+
+// candleSyncLocked возвращает синхронизацию свечей, вызывается под s.mu.
+// Нестандартные интервалы собираются из синхронизации базового интервала.
+func (s *SubData) candleSyncLocked(symbol string, interval cdl.Interval) (*cdl.CandleSync, error) {
 	key := fmt.Sprintf("%s-%d", symbol, interval)
 	if candleSync, ok := s.candleSyncs[key]; ok {
 		return candleSync, nil
 	}
-	newCandleSync := cdl.NewCandleSync(s.ctx, symbol, interval, s.bufferSize, s.dataProvider)
+
+	var provider cdl.CandleProvider = s.dataProvider
+	if !interval.IsNative() {
+		base := interval.Base()
+		baseSync, err := s.candleSyncLocked(symbol, base)
+		if err != nil {
+			return nil, err
+		}
+		provider = cdl.NewResampler(baseSync, base)
+	}
+	newCandleSync := cdl.NewCandleSync(s.ctx, symbol, interval, s.bufferSize, provider)
 	if err := newCandleSync.StartSync(); err != nil {
 		return nil, err
 	}
@@ -70,6 +96,9 @@ func (s *SubData) ReadConfirmCandles(symbol string, interval cdl.Interval, limit
 }
 
 func (s *SubData) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	if !interval.IsNative() {
+		return cdl.NewResampler(s.dataProvider, interval.Base()).GetCandles(symbol, interval, limit)
+	}
 	return s.dataProvider.GetCandles(symbol, interval, limit)
 }
 
@@ -85,6 +114,112 @@ func (s *SubData) GetInstrumentInfo(symbol string) (*InstrumentInfo, error) {
 	return &instrumentInfo, nil
 }
 
+// GetOrderBook возвращает локальную книгу ордеров, поддерживаемую потоком поставщика.
+// Книга общая для всех подписчиков с тем же символом и глубиной.
+func (s *SubData) GetOrderBook(symbol string, depth int) (*book.OrderBook, error) {
+	orderBookProvider, ok := s.dataProvider.(book.OrderBookProvider)
+	if !ok {
+		return nil, fmt.Errorf("data provider does not support order book stream")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fmt.Sprintf("%s-%d", symbol, depth)
+	if orderBook, ok := s.orderBooks[key]; ok {
+		return orderBook, nil
+	}
+	orderBook, err := book.Start(s.ctx, orderBookProvider, symbol, depth)
+	if err != nil {
+		return nil, err
+	}
+	s.orderBooks[key] = orderBook
+	return orderBook, nil
+}
+
+// SubscribeTrades подписывает канал на поток публичных сделок инструмента
+func (s *SubData) SubscribeTrades(symbol string, ch chan<- *tick.Tick) (chan<- struct{}, error) {
+	tickProvider, ok := s.dataProvider.(tick.TickProvider)
+	if !ok {
+		return nil, fmt.Errorf("data provider does not support trade stream")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tickSync, ok := s.tickSyncs[symbol]
+	if !ok {
+		var err error
+		if tickSync, err = tick.Start(s.ctx, tickProvider, symbol); err != nil {
+			return nil, err
+		}
+		s.tickSyncs[symbol] = tickSync
+	}
+	return tickSync.Subscribe(ch), nil
+}
+
+func (s *SubData) getTickerSync(symbol string) (*ticker.Sync, error) {
+	tickerProvider, ok := s.dataProvider.(ticker.TickerProvider)
+	if !ok {
+		return nil, fmt.Errorf("data provider does not support ticker stream")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tickerSync, ok := s.tickerSyncs[symbol]; ok {
+		return tickerSync, nil
+	}
+	tickerSync, err := ticker.Start(s.ctx, tickerProvider, symbol)
+	if err != nil {
+		return nil, err
+	}
+	s.tickerSyncs[symbol] = tickerSync
+	return tickerSync, nil
+}
+
+// SubscribeTicker подписывает канал на поток тикера инструмента
+func (s *SubData) SubscribeTicker(symbol string, ch chan<- *ticker.Ticker) (chan<- struct{}, error) {
+	tickerSync, err := s.getTickerSync(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return tickerSync.Subscribe(ch), nil
+}
+
+// LastTicker возвращает последний тикер инструмента, запуская поток при первом обращении.
+// Сразу после запуска потока тикер может быть еще не получен.
+func (s *SubData) LastTicker(symbol string) (ticker.Ticker, bool, error) {
+	tickerSync, err := s.getTickerSync(symbol)
+	if err != nil {
+		return ticker.Ticker{}, false, err
+	}
+	t, ok := tickerSync.Last()
+	return t, ok, nil
+}
+
+// OrderProvider - необязательное расширение DataProvider для запроса ордера по ID
+type OrderProvider interface {
+	GetOrder(orderId string) ([]byte, error)
+}
+
+// GetOrder возвращает текущее состояние ордера по ID
+func (s *SubData) GetOrder(orderId string) (*Order, error) {
+	orderProvider, ok := s.dataProvider.(OrderProvider)
+	if !ok {
+		return nil, fmt.Errorf("data provider does not support orders")
+	}
+	b, err := orderProvider.GetOrder(orderId)
+	if err != nil {
+		return nil, err
+	}
+	var order Order
+	if err = json.Unmarshal(b, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
 func (s *SubData) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -92,4 +227,13 @@ func (s *SubData) Clear() {
 	for k := range s.candleSyncs {
 		delete(s.candleSyncs, k)
 	}
+	for k := range s.orderBooks {
+		delete(s.orderBooks, k)
+	}
+	for k := range s.tickSyncs {
+		delete(s.tickSyncs, k)
+	}
+	for k := range s.tickerSyncs {
+		delete(s.tickerSyncs, k)
+	}
 }
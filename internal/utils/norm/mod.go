@@ -11,10 +11,11 @@ type Number interface {
 	constraints.Integer | constraints.Float
 }
 
-func ZScore[V Number](s []V) float64 {
+// MeanStd возвращает среднее и стандартное отклонение (по генеральной совокупности)
+func MeanStd[V Number](s []V) (float64, float64) {
 	n := len(s)
-	if n <= 1 {
-		return 0
+	if n == 0 {
+		return 0, 0
 	}
 	mean := numeric.Avg(s)
 	var sumSqr float64
@@ -26,7 +27,15 @@ func ZScore[V Number](s []V) float64 {
 	if variance < 0 {
 		variance = 0
 	}
-	stdDev := math.Sqrt(variance)
+	return mean, math.Sqrt(variance)
+}
+
+func ZScore[V Number](s []V) float64 {
+	n := len(s)
+	if n <= 1 {
+		return 0
+	}
+	mean, stdDev := MeanStd(s)
 	if stdDev == 0 {
 		return 0
 	} else {
@@ -34,6 +43,13 @@ func ZScore[V Number](s []V) float64 {
 	}
 }
 
+// BollingerBands возвращает среднюю и полосы mean ± k*std по последним period значениям
+func BollingerBands[V Number](s []V, period int, k float64) (mid, upper, lower float64) {
+	n := len(s)
+	mean, stdDev := MeanStd(s[max(0, n-period):])
+	return mean, mean + k*stdDev, mean - k*stdDev
+}
+
 func ZScoreNormalize[V Number](s []V, period int) []float64 {
 	n := len(s)
 	if n < period {
@@ -172,3 +172,11 @@ func FloorFloat(val float64, precision int) float64 {
 	ratio := math.Pow(10, float64(precision))
 	return math.Floor(val*ratio) / ratio
 }
+
+// RoundToStep округляет float64 до ближайшего значения, кратного step.
+func RoundToStep(val, step float64) float64 {
+	if step <= 0 {
+		return val
+	}
+	return RoundFloat(math.Round(val/step)*step, DecimalPlaces(step))
+}
@@ -0,0 +1,233 @@
+package slogx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var _ slog.Handler = (*RedactHandler)(nil)
+
+// DefaultRedactMask - замена скрытого значения
+const DefaultRedactMask = "[REDACTED]"
+
+// DefaultRedactKeys - ключи атрибутов с секретами и идентификаторами аккаунтов
+var DefaultRedactKeys = []string{
+	"apiKey", "apiSecret", "secret", "passphrase", "password", "token",
+	"signature", "sign", "accountId", "uid", "userId", "memberId",
+}
+
+// DefaultRedactPatterns - секреты в параметрах запросов внутри строк (например, в тексте ошибок)
+var DefaultRedactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(signature|sign|api_?key|api_?secret|passphrase)=[^&\s"]+`),
+}
+
+// RedactOptions - параметры скрытия и усечения значений
+type RedactOptions struct {
+	Keys     []string         // Ключи атрибутов, значения которых скрываются (без учета регистра, "_" и "-")
+	Patterns []*regexp.Regexp // Совпадения в строках, которые скрываются
+	MaxLen   int              // Максимальная длина строки или JSON составного значения (0 - без ограничения)
+	Mask     string           // Замена скрытого значения (по умолчанию DefaultRedactMask)
+}
+
+// Redactor скрывает секреты и усекает большие значения атрибутов
+type Redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+	maxLen   int
+	mask     string
+}
+
+// NewRedactor создает Redactor с параметрами opts
+func NewRedactor(opts RedactOptions) *Redactor {
+	r := &Redactor{
+		keys:     make(map[string]struct{}, len(opts.Keys)),
+		patterns: opts.Patterns,
+		maxLen:   opts.MaxLen,
+		mask:     opts.Mask,
+	}
+	if r.mask == "" {
+		r.mask = DefaultRedactMask
+	}
+	for _, k := range opts.Keys {
+		r.keys[normalizeKey(k)] = struct{}{}
+	}
+	return r
+}
+
+// normalizeKey приводит ключ к виду для сравнения: apiKey, api_key и API-KEY совпадают
+func normalizeKey(k string) string {
+	k = strings.ToLower(k)
+	k = strings.ReplaceAll(k, "_", "")
+	return strings.ReplaceAll(k, "-", "")
+}
+
+func (r *Redactor) isSecretKey(k string) bool {
+	_, ok := r.keys[normalizeKey(k)]
+	return ok
+}
+
+// String скрывает совпадения шаблонов и усекает строку
+func (r *Redactor) String(s string) string {
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, r.mask)
+	}
+	return r.truncate(s)
+}
+
+// truncate усекает строку до maxLen байт по границе символа
+func (r *Redactor) truncate(s string) string {
+	if r.maxLen <= 0 || len(s) <= r.maxLen {
+		return s
+	}
+	n := r.maxLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s...(truncated %d bytes)", s[:n], len(s)-n)
+}
+
+// Attr возвращает атрибут со скрытыми секретами и усеченными значениями
+func (r *Redactor) Attr(a slog.Attr) slog.Attr {
+	if r.isSecretKey(a.Key) {
+		return slog.String(a.Key, r.mask)
+	}
+	a.Value = r.Value(a.Value)
+	return a
+}
+
+// Value возвращает значение со скрытыми секретами и усеченными строками.
+// Составные значения (структуры, отображения, срезы) обходятся через их JSON представление.
+func (r *Redactor) Value(v slog.Value) slog.Value {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(r.String(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			redacted[i] = r.Attr(a)
+		}
+		return slog.GroupValue(redacted...)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.StringValue(r.String(err.Error()))
+		}
+		decoded, err := decodeJSON(v.Any())
+		if err != nil {
+			return v
+		}
+		decoded = r.walk(decoded)
+		if r.maxLen > 0 {
+			if data, err := json.Marshal(decoded); err == nil && len(data) > r.maxLen {
+				return slog.StringValue(r.truncate(string(data)))
+			}
+		}
+		return slog.AnyValue(decoded)
+	}
+	return v
+}
+
+// walk скрывает секреты в декодированном JSON значении
+func (r *Redactor) walk(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if r.isSecretKey(k) {
+				v[k] = r.mask
+				continue
+			}
+			v[k] = r.walk(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.walk(item)
+		}
+		return v
+	case string:
+		return r.String(v)
+	}
+	return v
+}
+
+// JSON возвращает форматированный JSON значения v со скрытыми секретами.
+// Используется для вывода конфигурации и данных аккаунта.
+func (r *Redactor) JSON(v any) ([]byte, error) {
+	decoded, err := decodeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(r.walk(decoded), "", "    ")
+}
+
+// decodeJSON преобразует значение в дерево JSON. Числа сохраняются как json.Number,
+// чтобы не терять точность больших идентификаторов.
+func decodeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// RedactHandler - промежуточный обработчик, скрывающий секреты и усекающий большие
+// атрибуты и сообщение перед передачей записи следующему обработчику
+type RedactHandler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+// Redact оборачивает обработчик next обработчиком скрытия секретов
+func Redact(next slog.Handler, opts RedactOptions) slog.Handler {
+	return &RedactHandler{
+		next:     next,
+		redactor: NewRedactor(opts),
+	}
+}
+
+func (h *RedactHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redactor.String(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.Attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactor.Attr(a)
+	}
+	return &RedactHandler{
+		next:     h.next.WithAttrs(redacted),
+		redactor: h.redactor,
+	}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &RedactHandler{
+		next:     h.next.WithGroup(name),
+		redactor: h.redactor,
+	}
+}
@@ -0,0 +1,78 @@
+package main_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/trading"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPositionLedger(t *testing.T) {
+	l := trading.NewPositionLedger(3)
+
+	l.Apply(trading.Fill{Qty: 1, Price: 100, Fee: .1})
+	l.Apply(trading.Fill{Qty: 3, Price: 200, Fee: .1})
+	p := l.Position()
+	if !almostEqual(p.Qty, 4) || !almostEqual(p.AvgPrice, 175) {
+		t.Fatalf("unexpected position after adding: %+v", p)
+	}
+
+	realized := l.Apply(trading.Fill{Qty: -1, Price: 195})
+	if !almostEqual(realized, 20) {
+		t.Fatalf("unexpected realized pnl on reduce: %f", realized)
+	}
+	if p = l.Position(); !almostEqual(p.AvgPrice, 175) {
+		t.Fatalf("avg price must not change on reduce: %+v", p)
+	}
+
+	// Переворот через ноль: 3 закрываются, 2 открывают шорт по 150
+	realized = l.Apply(trading.Fill{Qty: -5, Price: 150})
+	p = l.Position()
+	if !almostEqual(realized, -75) || !almostEqual(p.Qty, -2) || !almostEqual(p.AvgPrice, 150) {
+		t.Fatalf("unexpected flip result: realized=%f %+v", realized, p)
+	}
+	if !almostEqual(p.UnrealizedPnL(140), 20) {
+		t.Fatalf("unexpected unrealized pnl: %f", p.UnrealizedPnL(140))
+	}
+
+	l.ApplyFunding(trading.NewFundingPayment(1, -.0015, -2, 100))
+	p = l.Position()
+	if !almostEqual(p.RealizedPnL, -55) || !almostEqual(p.NetPnL(), -55-.2-.3) {
+		t.Fatalf("unexpected pnl totals: %+v", p)
+	}
+}
+
+func TestPositionLedgerApplyOrder(t *testing.T) {
+	l := trading.NewPositionLedger(3)
+	o := &trading.Order{ID: "1", Symbol: "BTCUSDT", Qty: -2, ExecQty: -1, ExecValue: -100, Fee: .05}
+
+	if _, _, ok := l.ApplyOrder("a", o); !ok {
+		t.Fatal("partial fill not applied")
+	}
+	o.ExecQty, o.ExecValue, o.Fee, o.IsClosed = -2, -220, .1, true
+	fill, _, ok := l.ApplyOrder("a", o)
+	if !ok || !almostEqual(fill.Qty, -1) || !almostEqual(fill.Price, 120) || !almostEqual(fill.Fee, .05) {
+		t.Fatalf("unexpected incremental fill: %+v", fill)
+	}
+	if p := l.Position(); !almostEqual(p.Qty, -2) || !almostEqual(p.AvgPrice, 110) {
+		t.Fatalf("unexpected position: %+v", p)
+	}
+}
+
+func TestPositionLedgerFunding(t *testing.T) {
+	l := trading.NewPositionLedger(3)
+	l.Apply(trading.Fill{Qty: 2, Price: 100})
+
+	l.ApplyFunding(trading.NewFundingPayment(1, .001, 2, 100))
+	l.ApplyFunding(trading.NewFundingPayment(2, -.0005, -1, 100))
+	if p := l.Position(); !almostEqual(p.Funding, -.2-.05) {
+		t.Fatalf("unexpected funding total: %+v", p)
+	}
+	if payments := l.FundingPayments(); len(payments) != 2 || !almostEqual(payments[0].Amount, -.2) {
+		t.Fatalf("unexpected funding payments: %+v", payments)
+	}
+}
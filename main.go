@@ -12,16 +12,37 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/joho/godotenv"
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/binance"
 	"github.com/nikita55612/goTradingBot/internal/broker/bybit"
+	"github.com/nikita55612/goTradingBot/internal/broker/okx"
+	"github.com/nikita55612/goTradingBot/internal/pkg/credentials"
 	"github.com/nikita55612/goTradingBot/internal/trading"
 	"github.com/nikita55612/goTradingBot/internal/trading/predict/pyapp"
-	"github.com/nikita55612/goTradingBot/internal/trading/strategies"
+	_ "github.com/nikita55612/goTradingBot/internal/trading/strategies"
 	"github.com/nikita55612/goTradingBot/internal/utils/slogx"
 )
 
 var logo = []byte{0xa, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x5f, 0x5f, 0x5f, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x5f, 0x5f, 0x5f, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x5f, 0x5f, 0xa, 0x20, 0x20, 0x5f, 0x5f, 0x5f, 0x20, 0x5f, 0x5f, 0x5f, 0x5f, 0x2f, 0x5f, 0x20, 0x20, 0x5f, 0x5f, 0x2f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x20, 0x5f, 0x5f, 0x5f, 0x5f, 0x2f, 0x20, 0x28, 0x5f, 0x29, 0x5f, 0x5f, 0x20, 0x20, 0x5f, 0x5f, 0x5f, 0x20, 0x5f, 0x2f, 0x20, 0x5f, 0x20, 0x29, 0x5f, 0x5f, 0x5f, 0x20, 0x20, 0x2f, 0x20, 0x2f, 0x5f, 0xa, 0x20, 0x2f, 0x20, 0x5f, 0x20, 0x60, 0x2f, 0x20, 0x5f, 0x20, 0x5c, 0x2f, 0x20, 0x2f, 0x20, 0x2f, 0x20, 0x5f, 0x5f, 0x2f, 0x20, 0x5f, 0x20, 0x60, 0x2f, 0x20, 0x5f, 0x20, 0x20, 0x2f, 0x20, 0x2f, 0x20, 0x5f, 0x20, 0x5c, 0x2f, 0x20, 0x5f, 0x20, 0x60, 0x2f, 0x20, 0x5f, 0x20, 0x20, 0x2f, 0x20, 0x5f, 0x20, 0x5c, 0x2f, 0x20, 0x5f, 0x5f, 0x2f, 0xa, 0x20, 0x5c, 0x5f, 0x2c, 0x20, 0x2f, 0x5c, 0x5f, 0x5f, 0x5f, 0x2f, 0x5f, 0x2f, 0x20, 0x2f, 0x5f, 0x2f, 0x20, 0x20, 0x5c, 0x5f, 0x2c, 0x5f, 0x2f, 0x5c, 0x5f, 0x2c, 0x5f, 0x2f, 0x5f, 0x2f, 0x5f, 0x2f, 0x2f, 0x5f, 0x2f, 0x5c, 0x5f, 0x2c, 0x20, 0x2f, 0x5f, 0x5f, 0x5f, 0x5f, 0x2f, 0x5c, 0x5f, 0x5f, 0x5f, 0x2f, 0x5c, 0x5f, 0x5f, 0x2f, 0xa, 0x2f, 0x5f, 0x5f, 0x5f, 0x2f, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x2f, 0x5f, 0x5f, 0x5f, 0x2f, 0xa}
 
+// redactOptions - параметры скрытия секретов и усечения больших значений в логах
+// и выводе конфигурации
+var redactOptions = slogx.RedactOptions{
+	Keys:     slogx.DefaultRedactKeys,
+	Patterns: slogx.DefaultRedactPatterns,
+	MaxLen:   4096,
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "credentials" {
+		if err := runCredentialsCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("\033[36m" + string(logo) + "\033[0m")
 
 	ctx, stop := signal.NotifyContext(
@@ -41,6 +62,11 @@ func main() {
 		trading.DefaultTradingBotConfigPath,
 		"path to configuration file",
 	)
+	credentialsPath := flag.String(
+		"credentials",
+		credentials.DefaultStorePath,
+		"path to encrypted credentials store",
+	)
 	flag.Parse()
 
 	_, err := os.Stat(*configPath)
@@ -87,47 +113,84 @@ func main() {
 	}
 	defer logFile.Close()
 
-	logger := slog.New(slogx.Fanout(
-		slog.NewJSONHandler(logFile, nil),
-		slog.NewJSONHandler(os.Stdout, nil),
+	logger := slog.New(slogx.Redact(
+		slogx.Fanout(
+			slog.NewJSONHandler(logFile, nil),
+			slog.NewJSONHandler(os.Stdout, nil),
+		),
+		redactOptions,
 	))
 
-	cli := bybit.NewClientFromEnv(bybit.WithCategory("linear"))
-	accountInfo, err := cli.GetAccountInfo()
-	if err != nil {
-		panic(err)
-	}
-	accountInfoData, _ := json.MarshalIndent(&accountInfo, "", "    ")
-	fmt.Println("accountInfo:", string(accountInfoData))
-
-	tb := trading.NewTradingBot(ctx, cli.BrokerImpl(), logger)
+	// Ключи аккаунтов читаются из окружения, файл .env необязателен
+	_ = godotenv.Load()
 
-	cfgData, _ := json.MarshalIndent(&config, "", "    ")
-	fmt.Println("config:", string(cfgData))
+	type account struct {
+		name        string
+		tb          *trading.TradingBot
+		strategyIDs []string
+	}
+	var store *credentials.Store
+	openStore := func() (*credentials.Store, error) {
+		if store != nil {
+			return store, nil
+		}
+		passphrase, err := credentials.ReadPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		store, err = credentials.Open(*credentialsPath, passphrase)
+		return store, err
+	}
 
-	addedStrategyIDs := []string{}
-	for _, sc := range config.Strategies {
-		strategy, err := strategies.NewTrendStrategy(&sc)
+	strategiesByAccount := config.StrategiesByAccount()
+	accounts := make([]*account, 0, len(config.Accounts))
+	for i := range config.Accounts {
+		accountConfig := &config.Accounts[i]
+		c, err := accountCredentials(accountConfig, openStore)
 		if err != nil {
-			fmt.Printf("error creating strategy: %s\n", err)
-			continue
+			panic(err)
 		}
-		id, err := tb.AddStrategy(strategy)
+		b, err := newBroker(accountConfig, c)
 		if err != nil {
-			fmt.Printf("strategy initialization error: %s\n", err)
-			continue
+			panic(err)
+		}
+		accounts = append(accounts, &account{
+			name: accountConfig.Name,
+			tb:   trading.NewTradingBot(ctx, b, logger.With("account", accountConfig.Name)),
+		})
+	}
+
+	cfgData, _ := slogx.NewRedactor(redactOptions).JSON(&config)
+	fmt.Println("config:", string(cfgData))
+
+	addedStrategies := 0
+	for _, a := range accounts {
+		for _, sc := range strategiesByAccount[a.name] {
+			strategy, err := trading.NewStrategy(&sc)
+			if err != nil {
+				fmt.Printf("error creating strategy: %s\n", err)
+				continue
+			}
+			id, err := a.tb.AddStrategy(strategy)
+			if err != nil {
+				fmt.Printf("strategy initialization error: %s\n", err)
+				continue
+			}
+			a.strategyIDs = append(a.strategyIDs, id)
+			addedStrategies++
 		}
-		addedStrategyIDs = append(addedStrategyIDs, id)
 	}
 
 	fmt.Printf(
 		"added %d strategies out of %d\n",
-		len(addedStrategyIDs),
+		addedStrategies,
 		len(config.Strategies),
 	)
 
-	if len(addedStrategyIDs) == 0 {
-		tb.Stop()
+	if addedStrategies == 0 {
+		for _, a := range accounts {
+			a.tb.Stop()
+		}
 		return
 	}
 
@@ -138,11 +201,83 @@ func main() {
 		time.Sleep(time.Second)
 	}
 
-	for _, id := range addedStrategyIDs {
-		if err := tb.LaunchStrategy(id); err != nil {
-			fmt.Printf("strategy launch error: %s\n", err)
+	for _, a := range accounts {
+		for _, id := range a.strategyIDs {
+			if err := a.tb.LaunchStrategy(id); err != nil {
+				fmt.Printf("strategy launch error (%s): %s\n", a.name, err)
+			}
 		}
 	}
 
 	<-ctx.Done()
 }
+
+// newBroker создает брокера аккаунта с ключами c. Для bybit ключи проверяются
+// запросом информации об аккаунте.
+func newBroker(a *trading.AccountConfig, c credentials.Credential) (broker.Broker, error) {
+	switch a.Exchange {
+	case trading.ExchangeBybit:
+		baseURL, publicWS, tradeWS := bybit.MAINNET, bybit.PUBLICWS, bybit.TRADEWS
+		switch a.Network {
+		case trading.NetworkTestnet:
+			baseURL, publicWS, tradeWS = bybit.TESTNET, bybit.PUBLICWSTESTNET, bybit.TRADEWSTESTNET
+		case trading.NetworkDemo:
+			// Демо-торговля использует публичные потоки основной сети
+			// и не поддерживает WebSocket API ордеров
+			baseURL, tradeWS = bybit.DEMO, ""
+		}
+		if a.BaseURL != "" {
+			baseURL = a.BaseURL
+		}
+		opts := []bybit.Option{
+			bybit.WithCategory(a.Category),
+			bybit.WithBaseURL(baseURL),
+			bybit.WithPublicWS(publicWS),
+		}
+		if a.TradeWS && tradeWS != "" {
+			opts = append(opts, bybit.WithTradeWS(tradeWS))
+		}
+		cli := bybit.NewClient(c.APIKey, c.APISecret, opts...)
+		accountInfo, err := cli.GetAccountInfo()
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", a.Name, err)
+		}
+		accountInfoData, _ := slogx.NewRedactor(redactOptions).JSON(&accountInfo)
+		fmt.Printf("accountInfo (%s): %s\n", a.Name, accountInfoData)
+		return cli.BrokerImpl(), nil
+
+	case trading.ExchangeBinance:
+		if a.Category != "linear" {
+			return nil, fmt.Errorf("account %s: binance supports only linear category", a.Name)
+		}
+		baseURL, wsURL := binance.MAINNET, binance.STREAMWS
+		if a.Network != trading.NetworkMainnet {
+			baseURL, wsURL = binance.TESTNET, binance.STREAMWSTESTNET
+		}
+		if a.BaseURL != "" {
+			baseURL = a.BaseURL
+		}
+		cli := binance.NewClient(
+			c.APIKey, c.APISecret,
+			binance.WithBaseURL(baseURL),
+			binance.WithWSURL(wsURL),
+		)
+		return cli.BrokerImpl(), nil
+
+	case trading.ExchangeOKX:
+		if c.Passphrase == "" {
+			return nil, fmt.Errorf("account %s: okx api passphrase must be specified", a.Name)
+		}
+		opts := []okx.Option{okx.WithCategory(a.Category)}
+		if a.Network != trading.NetworkMainnet {
+			opts = append(opts, okx.WithDemo())
+		}
+		if a.BaseURL != "" {
+			opts = append(opts, okx.WithBaseURL(a.BaseURL))
+		}
+		cli := okx.NewClient(c.APIKey, c.APISecret, c.Passphrase, opts...)
+		return cli.BrokerImpl(), nil
+	}
+
+	return nil, fmt.Errorf("account %s: unsupported exchange %q", a.Name, a.Exchange)
+}
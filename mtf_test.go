@@ -0,0 +1,107 @@
+package main_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+)
+
+// mtfBase - время закрытия текущих баров M1 и M5 в тестовом провайдере
+const mtfBase int64 = 1_700_000_100_000
+
+type stubProvider struct {
+	streams map[cdl.Interval]chan *cdl.CandleStreamData
+}
+
+func (p *stubProvider) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	return p.streams[interval], nil
+}
+
+func (p *stubProvider) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	step := int64(interval.AsMilli())
+	candles := make([]cdl.Candle, limit)
+	for i := range candles {
+		candles[i] = cdl.Candle{Time: mtfBase - int64(limit-i)*step, C: float64(i)}
+	}
+	return candles, nil
+}
+
+func (p *stubProvider) GetInstrumentInfo(symbol string) ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+func TestIntervalAlignment(t *testing.T) {
+	if got := cdl.H4.OpenTime(mtfBase); got != 1_699_992_000_000 {
+		t.Fatalf("unexpected H4 open time: %d", got)
+	}
+	// 2023-11-13 00:00 UTC - понедельник
+	if got := cdl.D7.OpenTime(mtfBase); got != 1_699_833_600_000 {
+		t.Fatalf("unexpected D7 open time: %d", got)
+	}
+	// 2023-11-01 00:00 UTC - 2023-12-01 00:00 UTC
+	if got := cdl.D30.CloseTime(1_698_796_800_000); got != 1_701_388_800_000 {
+		t.Fatalf("unexpected D30 close time: %d", got)
+	}
+}
+
+func TestMultiFrame(t *testing.T) {
+	provider := &stubProvider{streams: map[cdl.Interval]chan *cdl.CandleStreamData{
+		cdl.M1: make(chan *cdl.CandleStreamData),
+		cdl.M5: make(chan *cdl.CandleStreamData),
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subData := trading.NewSubData(ctx, provider, 10)
+	mf, err := subData.MultiFrame("BTCUSDT", cdl.M5, cdl.M1, cdl.M5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := mf.View(cdl.M5, 2, mtfBase-300_000)
+	if err != nil || len(view) != 2 || view[1].Time != mtfBase-600_000 {
+		t.Fatalf("unexpected view: %+v %v", view, err)
+	}
+	if view, _ := mf.View(cdl.M5, 2, mtfBase-300_001); view[1].Time != mtfBase-900_000 {
+		t.Fatalf("view leaks unclosed candle: %+v", view)
+	}
+
+	events := make(chan *trading.MultiFrameEvent, 2)
+	done, err := mf.Subscribe(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer close(done)
+
+	send := func(interval cdl.Interval, openTime int64) {
+		provider.streams[interval] <- &cdl.CandleStreamData{
+			Candle:   cdl.Candle{Time: openTime},
+			Interval: interval,
+			Confirm:  true,
+		}
+	}
+	receive := func() *trading.MultiFrameEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("multi-frame event not received")
+		}
+		return nil
+	}
+
+	send(cdl.M1, mtfBase-120_000)
+	if event := receive(); event.AllClosed || event.Time != mtfBase-60_000 || len(event.Closed) != 1 {
+		t.Fatalf("unexpected M1 event: %+v", event)
+	}
+
+	send(cdl.M5, mtfBase-300_000)
+	send(cdl.M1, mtfBase-60_000)
+	event := receive()
+	if !event.AllClosed || event.Time != mtfBase || event.Candles[cdl.M5].Time != mtfBase-300_000 {
+		t.Fatalf("unexpected combined event: %+v", event)
+	}
+}
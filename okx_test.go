@@ -0,0 +1,165 @@
+package main_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nikita55612/goTradingBot/internal/broker"
+	"github.com/nikita55612/goTradingBot/internal/broker/okx"
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+func TestOKXBroker(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	signed := func(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(r.Header.Get("OK-ACCESS-TIMESTAMP") + r.Method + r.URL.RequestURI() + string(body)))
+		if r.Header.Get("OK-ACCESS-SIGN") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) ||
+			r.Header.Get("OK-ACCESS-PASSPHRASE") != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":"50113","msg":"Invalid Sign","data":[]}`))
+			return nil, false
+		}
+		return body, true
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v5/public/instruments", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT-SWAP","instType":"SWAP",
+			"ctVal":"0.01","tickSz":"0.1","lotSz":"0.1","minSz":"1","state":"live"}]}`))
+	})
+	mux.HandleFunc("/api/v5/market/ticker", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT-SWAP","last":"50000"}]}`))
+	})
+	mux.HandleFunc("/api/v5/market/candles", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"0","msg":"","data":[
+			["120000","1.5","3","1","2","2000","20","40","0"],
+			["60000","1","2","0.5","1.5","1000","10","15","1"]]}`))
+	})
+	mux.HandleFunc("/api/v5/trade/order", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := signed(w, r)
+		if !ok {
+			return
+		}
+		if r.Method == http.MethodPost {
+			var params map[string]string
+			json.Unmarshal(body, &params)
+			if params["sz"] != "3" || params["side"] != "sell" || params["ordType"] != "limit" || params["tdMode"] != "cross" {
+				t.Errorf("unexpected order params: %s", body)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"42","sCode":"0","sMsg":""}]}`))
+			return
+		}
+		w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT-SWAP","ordId":"42","side":"sell",
+			"px":"50000","sz":"3","avgPx":"50000","accFillSz":"1","fee":"-0.2","state":"partially_filled",
+			"cTime":"1","uTime":"2"}]}`))
+	})
+	mux.HandleFunc("/api/v5/trade/batch-orders", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := signed(w, r); !ok {
+			return
+		}
+		w.Write([]byte(`{"code":"2","msg":"","data":[{"ordId":"1","sCode":"0"},
+			{"ordId":"","sCode":"51008","sMsg":"Insufficient balance"}]}`))
+	})
+	mux.HandleFunc("/ws/v5/business", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, msg, _ := conn.ReadMessage()
+		if !strings.Contains(string(msg), `"channel":"candle1m"`) {
+			t.Errorf("unexpected subscription: %s", msg)
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"subscribe","arg":{"channel":"candle1m"}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"arg":{"channel":"candle1m","instId":"BTC-USDT-SWAP"},
+			"data":[["60000","1","3","0.5","2","1000","10","20","1"]]}`))
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cli := okx.NewClient(
+		"key", "secret", "pass",
+		okx.WithBaseURL(server.URL),
+		okx.WithWSURL("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/v5/business"),
+	)
+	b := cli.BrokerImpl()
+
+	data, err := b.GetInstrumentInfo("BTC-USDT-SWAP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info map[string]any
+	json.Unmarshal(data, &info)
+	if info["qtyPrecision"] != 3. || info["minOrderAmt"] != 500. || info["tickSize"] != .1 || info["category"] != "linear" {
+		t.Fatalf("unexpected instrument info: %s", data)
+	}
+
+	candles, err := b.GetCandles("BTC-USDT-SWAP", cdl.M1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 2 || candles[0].Time != 60000 || candles[0].Volume != 10 || candles[1].Turnover != 40 {
+		t.Fatalf("unexpected candles: %+v", candles)
+	}
+
+	price := 50000.
+	orderId, err := b.PlaceOrder("BTC-USDT-SWAP", -.03, &price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orderId != "BTC-USDT-SWAP:42" {
+		t.Fatalf("unexpected order id: %s", orderId)
+	}
+	data, err = b.GetOrder(orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order map[string]any
+	json.Unmarshal(data, &order)
+	if order["qty"] != -.03 || order["execQty"] != -.01 || order["execValue"] != -500. || order["fee"] != .2 || order["isClosed"] != false {
+		t.Fatalf("unexpected order: %s", data)
+	}
+	if _, err := okx.NewClient("key", "wrong", "pass", okx.WithBaseURL(server.URL)).GetOrder(orderId); err == nil {
+		t.Fatal("expected signature error")
+	} else if e, ok := err.(*okx.Error); !ok || e.ServerResponseCode() != 50113 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, err := b.PlaceOrders([]broker.OrderParams{
+		{Symbol: "BTC-USDT-SWAP", Qty: .01},
+		{Symbol: "BTC-USDT-SWAP", Qty: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].OrderId != "BTC-USDT-SWAP:1" || results[0].Err != nil || results[1].Err == nil {
+		t.Fatalf("unexpected batch results: %+v", results)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := b.CandleStream(ctx, "BTC-USDT-SWAP", cdl.M1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case data := <-stream:
+		if !data.Confirm || data.Candle.Time != 119999 || data.Candle.H != 3 || data.Candle.Volume != 10 {
+			t.Fatalf("unexpected stream data: %+v", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no stream data")
+	}
+}
@@ -0,0 +1,40 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/book"
+)
+
+func TestOrderBook(t *testing.T) {
+	b := book.NewOrderBook("BTCUSDT")
+	b.Apply(&book.Update{Bids: []book.Level{{Price: 99, Size: 1}}, UpdateID: 1})
+	if b.Ready() {
+		t.Fatal("delta applied before snapshot")
+	}
+
+	b.Apply(&book.Update{
+		Snapshot: true,
+		Bids:     []book.Level{{Price: 100, Size: 2}, {Price: 99, Size: 3}},
+		Asks:     []book.Level{{Price: 101, Size: 1}, {Price: 102, Size: 4}},
+		UpdateID: 5,
+	})
+	b.Apply(&book.Update{
+		Bids:     []book.Level{{Price: 100, Size: 0}, {Price: 98, Size: 5}},
+		Asks:     []book.Level{{Price: 100.5, Size: 1}},
+		UpdateID: 6,
+	})
+
+	if bid, _ := b.BestBid(); bid.Price != 99 {
+		t.Fatalf("unexpected best bid: %+v", bid)
+	}
+	if !almostEqual(b.Spread(), 1.5) || !almostEqual(b.Mid(), 99.75) {
+		t.Fatalf("unexpected spread: %f", b.Spread())
+	}
+	if b.DepthAt(98) != 8 || b.DepthAt(101) != 2 || b.SizeAt(102) != 4 {
+		t.Fatal("unexpected depth")
+	}
+	if !almostEqual(b.Imbalance(1), .5) || b.UpdateID() != 6 {
+		t.Fatalf("unexpected imbalance: %f", b.Imbalance(1))
+	}
+}
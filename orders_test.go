@@ -0,0 +1,59 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit"
+)
+
+func TestOrderQueriesPagination(t *testing.T) {
+	var windows [][2]int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v5/order/realtime", func(w http.ResponseWriter, r *http.Request) {
+		order := map[string]any{
+			"orderId": "1", "symbol": "BTCUSDT", "side": "Sell", "qty": "0.02", "price": "100",
+			"avgPrice": "", "cumExecQty": "0", "cumExecValue": "0", "cumExecFee": "0",
+			"orderStatus": "New", "createdTime": "1", "updatedTime": "2",
+		}
+		result := map[string]any{"list": []any{order, order}, "nextPageCursor": "page2"}
+		if r.URL.Query().Get("settleCoin") != "USDT" {
+			t.Errorf("settle coin is not set: %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("cursor") == "page2" {
+			result = map[string]any{"list": []any{order}, "nextPageCursor": ""}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"retCode": 0, "result": result})
+	})
+	mux.HandleFunc("/v5/execution/list", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+		windows = append(windows, [2]int64{start, end})
+		w.Write([]byte(`{"retCode":0,"result":{"list":[{"execId":"e"}]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cli := bybit.NewClient("key", "secret", bybit.WithBaseURL(server.URL))
+	data, err := cli.BrokerImpl().GetOpenOrders("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var orders []map[string]any
+	json.Unmarshal(data, &orders)
+	if len(orders) != 3 || orders[0]["qty"] != -0.02 || orders[0]["isClosed"] != false {
+		t.Fatalf("unexpected open orders: %s", data)
+	}
+
+	const day = 24 * 60 * 60 * 1000
+	executions, err := cli.GetAllExecutions(bybit.OrderFilter{StartTime: 1, EndTime: 10 * day})
+	if err != nil || len(executions) != 2 {
+		t.Fatalf("unexpected executions: %d %v", len(executions), err)
+	}
+	if windows[0] != [2]int64{1, 7 * day} || windows[1] != [2]int64{7*day + 1, 10 * day} {
+		t.Fatalf("unexpected time windows: %v", windows)
+	}
+}
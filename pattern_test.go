@@ -0,0 +1,46 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+func TestCandlePatterns(t *testing.T) {
+	candles := []cdl.Candle{
+		{O: 110, H: 111, L: 104, C: 105},
+		{O: 105, H: 106, L: 99, C: 100},
+		{O: 100, H: 101, L: 94, C: 95},
+		{O: 95, H: 96, L: 89, C: 90},
+		{O: 90, H: 90.3, L: 84, C: 89.8},   // Молот
+		{O: 89, H: 90, L: 80, C: 81},       // Большая медвежья
+		{O: 80.5, H: 81, L: 80.1, C: 80.2}, // Звезда, внутренний бар
+		{O: 80.5, H: 89, L: 80, C: 88},     // Утренняя звезда, поглощение
+	}
+
+	if s := cdl.DetectPattern(candles, 4, cdl.Hammer); s <= 0 {
+		t.Fatal("hammer not detected")
+	}
+	if s := cdl.DetectPattern(candles, 6, cdl.InsideBar); s <= 0 {
+		t.Fatal("inside bar not detected")
+	}
+	if s := cdl.DetectPattern(candles, 7, cdl.MorningStar); s <= 0 {
+		t.Fatal("morning star not detected")
+	}
+	if s := cdl.DetectPattern(candles, 7, cdl.EveningStar); s != 0 {
+		t.Fatal("evening star detected in a bullish reversal")
+	}
+	if s := cdl.DetectPattern(candles, 2, cdl.ThreeBlackCrows); s <= 0 {
+		t.Fatal("three black crows not detected")
+	}
+
+	features := cdl.ListOfCandlePattern(candles, cdl.ThreeBlackCrows)
+	if features[2] >= 0 || features[0] != 0 {
+		t.Fatalf("unexpected pattern features: %v", features)
+	}
+	for _, s := range cdl.ScanPatterns(candles) {
+		if s.Strength <= 0 || s.Strength > 1 {
+			t.Fatalf("strength out of range: %+v", s)
+		}
+	}
+}
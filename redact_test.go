@@ -0,0 +1,52 @@
+package main_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/utils/slogx"
+)
+
+func TestRedactHandler(t *testing.T) {
+	var fileOut, stdOut bytes.Buffer
+	logger := slog.New(slogx.Redact(
+		slogx.Fanout(
+			slog.NewJSONHandler(&fileOut, nil),
+			slog.NewJSONHandler(&stdOut, nil),
+		),
+		slogx.RedactOptions{
+			Keys:     slogx.DefaultRedactKeys,
+			Patterns: slogx.DefaultRedactPatterns,
+			MaxLen:   80,
+		},
+	)).With("api_key", "leaked-key")
+
+	type account struct {
+		AccountId string `json:"accountId"`
+		OrderId   int64  `json:"orderId"`
+		Symbol    string `json:"symbol"`
+	}
+	logger.Info(
+		"request failed",
+		"error", errors.New("GET /fapi/v1/order?symbol=BTCUSDT&signature=abcdef0123"),
+		"account", account{AccountId: "1234567", OrderId: 9007199254740993, Symbol: "BTCUSDT"},
+		slog.Group("auth", "secret", "leaked-secret"),
+		"payload", strings.Repeat("x", 100),
+	)
+
+	for _, out := range []string{fileOut.String(), stdOut.String()} {
+		for _, leaked := range []string{"leaked-key", "abcdef0123", "1234567", "leaked-secret"} {
+			if strings.Contains(out, leaked) {
+				t.Fatalf("%q is not redacted: %s", leaked, out)
+			}
+		}
+		for _, want := range []string{`"orderId":9007199254740993`, `"symbol":"BTCUSDT"`, "truncated 20 bytes"} {
+			if !strings.Contains(out, want) {
+				t.Fatalf("%q not found in: %s", want, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+func TestParseCustomInterval(t *testing.T) {
+	for s, want := range map[string]cdl.Interval{"M10": 10, "H8": 480, "D2": 2880, "7": 7, "W": cdl.D7} {
+		got, err := cdl.ParseInterval(s)
+		if err != nil || got != want {
+			t.Fatalf("ParseInterval(%q) = %d, %v", s, got, err)
+		}
+	}
+	if cdl.Interval(480).AsString() != "H8" || cdl.Interval(480).Base() != cdl.H4 {
+		t.Fatal("unexpected custom interval representation")
+	}
+	if _, err := cdl.ParseInterval("H0"); err == nil {
+		t.Fatal("zero interval accepted")
+	}
+}
+
+func TestResample(t *testing.T) {
+	// 11 минутных свечей, начиная с середины 5-минутного бара
+	start := cdl.M5.OpenTime(mtfBase) + 3*60_000
+	candles := make([]cdl.Candle, 11)
+	for i := range candles {
+		candles[i] = cdl.Candle{Time: start + int64(i)*60_000, O: 1, H: float64(i), L: 1, C: float64(i), Volume: 1}
+	}
+
+	m5, err := cdl.Resample(candles, cdl.M1, cdl.M5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m5) != 1 || m5[0].Time != start+2*60_000 || m5[0].Volume != 5 || m5[0].H != 6 || m5[0].C != 6 {
+		t.Fatalf("unexpected resampled candles: %+v", m5)
+	}
+	if _, err := cdl.Resample(candles, cdl.M5, cdl.Interval(7)); err == nil {
+		t.Fatal("misaligned resample accepted")
+	}
+}
+
+func TestResamplerStream(t *testing.T) {
+	provider := &stubProvider{streams: map[cdl.Interval]chan *cdl.CandleStreamData{
+		cdl.M1: make(chan *cdl.CandleStreamData),
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m2 := cdl.Interval(2)
+	resampler := cdl.NewResampler(provider, cdl.M1)
+	history, err := resampler.GetCandles("BTCUSDT", m2, 3)
+	if err != nil || len(history) != 3 || history[2].Time != m2.OpenTime(mtfBase-60_000) {
+		t.Fatalf("unexpected history: %+v %v", history, err)
+	}
+
+	stream, err := resampler.CandleStream(ctx, "BTCUSDT", m2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Бар M2 открыт в mtfBase-60000 и закрывается в mtfBase+60000
+	send := func(openTime int64, confirm bool) *cdl.CandleStreamData {
+		provider.streams[cdl.M1] <- &cdl.CandleStreamData{
+			Candle:   cdl.Candle{Time: openTime + 59_999, O: 1, H: 2, L: 1, C: 2, Volume: 1},
+			Interval: cdl.M1,
+			Confirm:  confirm,
+		}
+		return <-stream
+	}
+
+	if data := send(mtfBase-60_000, true); data.Confirm || data.Interval != m2 {
+		t.Fatalf("bar confirmed before its close: %+v", data)
+	}
+	if data := send(mtfBase, false); data.Confirm || data.Candle.Volume != 2 {
+		t.Fatalf("unexpected partial bar: %+v", data)
+	}
+	data := send(mtfBase, true)
+	if !data.Confirm || data.Candle.Volume != 2 || data.Candle.Time != mtfBase+59_999 {
+		t.Fatalf("unexpected confirmed bar: %+v", data)
+	}
+}
@@ -0,0 +1,402 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/trading/strategies"
+)
+
+// strategyBase - время открытия первой исторической свечи тестовых стратегий
+const strategyBase int64 = 1_700_000_040_000
+
+// strategyProvider - поставщик данных стратегий с историей свечей и управляемым потоком
+type strategyProvider struct {
+	category string
+	history  map[string][]cdl.Candle
+	streams  map[string]chan *cdl.CandleStreamData
+	orders   map[string]*trading.Order
+	mu       sync.Mutex
+}
+
+func (p *strategyProvider) CandleStream(ctx context.Context, symbol string, interval cdl.Interval) (<-chan *cdl.CandleStreamData, error) {
+	return p.streams[symbol], nil
+}
+
+func (p *strategyProvider) GetCandles(symbol string, interval cdl.Interval, limit int) ([]cdl.Candle, error) {
+	history := p.history[symbol]
+	return history[max(0, len(history)-limit):], nil
+}
+
+func (p *strategyProvider) GetInstrumentInfo(symbol string) ([]byte, error) {
+	return fmt.Appendf(nil, `{"qtyPrecision":3,"minOrderAmt":1,"tickSize":0.1,"category":%q}`, p.category), nil
+}
+
+func (p *strategyProvider) GetOrder(orderId string) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	o, ok := p.orders[orderId]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", orderId)
+	}
+	return json.Marshal(o)
+}
+
+// strategyEnv запускает стратегию на тестовом поставщике и играет роль бота:
+// принимает запросы ордеров и отвечает на них вручную
+type strategyEnv struct {
+	t        *testing.T
+	provider *strategyProvider
+	subData  *trading.SubData
+	requests chan *trading.OrderRequest
+	next     map[string]int64 // Время открытия следующей подтверждаемой свечи
+	orderSeq int
+}
+
+// newStrategyEnv создает окружение, в котором история каждого символа - closes
+// (последняя свеча не подтверждена) с интервалом M1
+func newStrategyEnv(t *testing.T, category string, closes map[string][]float64) *strategyEnv {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	p := &strategyProvider{
+		category: category,
+		history:  make(map[string][]cdl.Candle),
+		streams:  make(map[string]chan *cdl.CandleStreamData),
+		orders:   make(map[string]*trading.Order),
+	}
+	e := &strategyEnv{
+		t:        t,
+		provider: p,
+		subData:  trading.NewSubData(ctx, p, 100),
+		requests: make(chan *trading.OrderRequest),
+		next:     make(map[string]int64),
+	}
+	for symbol, prices := range closes {
+		candles := make([]cdl.Candle, len(prices))
+		for i, c := range prices {
+			candles[i] = cdl.Candle{Time: strategyBase + int64(i)*60_000, O: c, H: c, L: c, C: c}
+		}
+		p.history[symbol] = candles
+		p.streams[symbol] = make(chan *cdl.CandleStreamData)
+		e.next[symbol] = candles[len(candles)-1].Time
+	}
+	return e
+}
+
+// launch инициализирует и запускает стратегию. Запуск может отправлять ордера,
+// поэтому выполняется в фоне, а его результат проверяется вызовом возвращенной функции.
+func (e *strategyEnv) launch(s trading.Strategy) (wait func()) {
+	s.Init(context.Background(), e.subData, e.requests)
+	errc := make(chan error, 1)
+	go func() { errc <- s.Launch() }()
+
+	return func() {
+		e.t.Helper()
+		select {
+		case err := <-errc:
+			if err != nil {
+				e.t.Fatal(err)
+			}
+		case <-time.After(2 * time.Second):
+			e.t.Fatal("strategy launch not completed")
+		}
+	}
+}
+
+// feed отправляет свечу в поток символа. Подписчики получают свечи без ожидания,
+// поэтому перед отправкой стратегии дается время закончить обработку предыдущей.
+func (e *strategyEnv) feed(symbol string, price float64, confirm bool) {
+	time.Sleep(50 * time.Millisecond)
+	openTime := e.next[symbol]
+	if confirm {
+		e.next[symbol] += 60_000
+	}
+	e.provider.streams[symbol] <- &cdl.CandleStreamData{
+		// Подтвержденные свечи потока несут время закрытия - 1 мс
+		Candle:   cdl.Candle{Time: openTime + 60_000 - 1, O: price, H: price, L: price, C: price},
+		Interval: cdl.M1,
+		Confirm:  confirm,
+	}
+}
+
+// request ожидает запрос ордера стратегии
+func (e *strategyEnv) request() *trading.OrderRequest {
+	e.t.Helper()
+	select {
+	case req := <-e.requests:
+		return req
+	case <-time.After(2 * time.Second):
+		e.t.Fatal("order request not received")
+	}
+	return nil
+}
+
+// noRequest проверяет, что стратегия не отправила запрос ордера
+func (e *strategyEnv) noRequest() {
+	e.t.Helper()
+	select {
+	case req := <-e.requests:
+		e.t.Fatalf("unexpected order request: %+v %+v", req, req.Order)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+// reply отправляет стратегии ответ по запросу req с копией ордера
+func (e *strategyEnv) reply(req *trading.OrderRequest, errMsg string) {
+	e.t.Helper()
+	update := &trading.OrderUpdate{
+		LinkId: req.LinkId,
+		Tag:    req.Tag,
+		Order:  req.Order.Clone(),
+		Error:  errMsg,
+	}
+	select {
+	case req.Reply <- update:
+	case <-time.After(2 * time.Second):
+		e.t.Fatal("order update not accepted")
+	}
+}
+
+// ack подтверждает размещение ордера
+func (e *strategyEnv) ack(req *trading.OrderRequest) {
+	e.orderSeq++
+	req.Order.Lock()
+	req.Order.ID = fmt.Sprintf("order-%d", e.orderSeq)
+	req.Order.Unlock()
+	e.reply(req, "")
+}
+
+// fill исполняет ордер полностью по цене price с комиссией fee и закрывает его
+func (e *strategyEnv) fill(req *trading.OrderRequest, price, fee float64) {
+	if req.Order.ID == "" {
+		e.ack(req)
+	}
+	req.Order.Lock()
+	req.Order.ExecQty = req.Order.Qty
+	req.Order.ExecValue = req.Order.Qty * price
+	req.Order.AvgPrice = price
+	req.Order.Fee = fee
+	req.Order.IsClosed = true
+	req.Order.Unlock()
+	e.reply(req, "")
+}
+
+func TestGridStrategy(t *testing.T) {
+	e := newStrategyEnv(t, "linear", map[string][]float64{"BTCUSDT": {100, 100, 100}})
+	closeOnStop := false
+	s, err := strategies.NewGridStrategy(&strategies.GridConfig{
+		Symbol:      "BTCUSDT",
+		LowerPrice:  90,
+		UpperPrice:  110,
+		Levels:      5,
+		OrderAmt:    100,
+		CloseOnStop: &closeOnStop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait := e.launch(s)
+
+	// Уровень 100 ближе всего к цене и остается свободным
+	ladder := make(map[float64]*trading.OrderRequest)
+	for range 4 {
+		req := e.request()
+		ladder[*req.Order.Price] = req
+	}
+	wait()
+	for price, side := range map[float64]float64{90: 1, 95: 1, 105: -1, 110: -1} {
+		req, ok := ladder[price]
+		if amt := req.Order.Qty * side * price; !ok || amt <= 99 || amt > 100 {
+			t.Fatalf("unexpected ladder order at %f: %+v", price, req)
+		}
+	}
+	e.noRequest()
+
+	// Ошибка размещения не освобождает уровень и не зеркалируется
+	e.reply(ladder[110], "insufficient balance")
+	e.noRequest()
+
+	// Исполнение покупки на 95 переносится продажей на уровень 100
+	buy := ladder[95]
+	e.ack(buy)
+	e.fill(buy, 95, 0)
+	mirror := e.request()
+	if *mirror.Order.Price != 100 || !almostEqual(mirror.Order.Qty, -buy.Order.Qty) {
+		t.Fatalf("unexpected mirror order: %+v", mirror.Order)
+	}
+	if p := s.Position(); !almostEqual(p.Qty, buy.Order.Qty) {
+		t.Fatalf("unexpected position: %+v", p)
+	}
+
+	// Частичное исполнение с закрытием (например, по orderTtl) выставляет остаток повторно,
+	// а исполненный объем не переносится на занятый уровень 100
+	sell := ladder[105]
+	e.ack(sell)
+	sell.Order.Lock()
+	sell.Order.ExecQty, sell.Order.ExecValue, sell.Order.IsClosed = -.5, -52.5, true
+	sell.Order.Unlock()
+	e.reply(sell, "")
+	if rest := e.request(); *rest.Order.Price != 105 || !almostEqual(rest.Order.Qty, sell.Order.Qty+.5) {
+		t.Fatalf("unexpected rest order: %+v", rest.Order)
+	}
+	e.noRequest()
+}
+
+func TestDCAStrategy(t *testing.T) {
+	e := newStrategyEnv(t, "spot", map[string][]float64{"BTCUSDT": {100, 100, 100}})
+	takeProfit := .01
+	s, err := strategies.NewDCAStrategy(&strategies.DCAConfig{
+		Symbol:       "BTCUSDT",
+		Interval:     "1",
+		EveryCandles: 1,
+		OrderAmt:     100,
+		TakeProfit:   &takeProfit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.launch(s)()
+
+	e.feed("BTCUSDT", 100, true)
+	buy := e.request()
+	if !almostEqual(buy.Order.Qty, 1) {
+		t.Fatalf("unexpected scheduled buy: %+v", buy.Order)
+	}
+	// Комиссия спота списывается в базовой монете: 0.1 / 100 = 0.001
+	e.fill(buy, 100, .1)
+
+	// Тейк-профит продает позицию за вычетом комиссии
+	e.feed("BTCUSDT", 102, false)
+	tp := e.request()
+	if !almostEqual(tp.Order.Qty, -.999) {
+		t.Fatalf("unexpected take profit: %+v", tp.Order)
+	}
+
+	// Ошибка размещения снимает блокировку выхода
+	e.reply(tp, "order rejected")
+	e.feed("BTCUSDT", 102, false)
+	tp = e.request()
+	if tp.LinkId == "" || !almostEqual(tp.Order.Qty, -.999) {
+		t.Fatalf("unexpected take profit retry: %+v", tp.Order)
+	}
+
+	// Пока ордер выхода активен, ни выход, ни плановые покупки не выставляются
+	e.ack(tp)
+	e.feed("BTCUSDT", 103, false)
+	e.feed("BTCUSDT", 103, true)
+	e.noRequest()
+
+	// Закрытие выхода с остатком меньше шага количества завершает цикл
+	e.fill(tp, 102, 0)
+	e.feed("BTCUSDT", 101, true)
+	if buy := e.request(); !almostEqual(buy.Order.Qty, .99) {
+		t.Fatalf("unexpected buy of the next cycle: %+v", buy.Order)
+	}
+}
+
+func TestMeanReversionStrategy(t *testing.T) {
+	zero := 0.
+	if _, err := strategies.NewMeanReversionStrategy(&strategies.MeanReversionConfig{
+		Symbol: "BTCUSDT", Interval: "1", Bands: strategies.BandsBollinger, Period: 5, EntryZ: &zero, OrderAmt: 100,
+	}); err == nil {
+		t.Fatal("expected error for bollinger bands of zero width")
+	}
+
+	e := newStrategyEnv(t, "spot", map[string][]float64{"BTCUSDT": {100, 101, 99, 100, 101, 99, 100}})
+	entryZ, closeOnStop := 1., false
+	s, err := strategies.NewMeanReversionStrategy(&strategies.MeanReversionConfig{
+		Symbol:      "BTCUSDT",
+		Interval:    "1",
+		Period:      5,
+		EntryZ:      &entryZ,
+		OrderAmt:    100,
+		CloseOnStop: &closeOnStop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.launch(s)()
+
+	e.feed("BTCUSDT", 90, true)
+	entry := e.request()
+	if !almostEqual(entry.Order.Qty, 1.111) {
+		t.Fatalf("unexpected entry: %+v", entry.Order)
+	}
+	// Пока ордер не закрыт, повторный сигнал входа пропускается
+	e.feed("BTCUSDT", 89, true)
+	e.noRequest()
+
+	// Ошибка размещения снимает блокировку
+	e.reply(entry, "order rejected")
+	e.feed("BTCUSDT", 85, true)
+	entry = e.request()
+	if !almostEqual(entry.Order.Qty, 1.176) {
+		t.Fatalf("unexpected entry retry: %+v", entry.Order)
+	}
+	// Комиссия спота списывается в базовой монете: 0.085 / 85 = 0.001
+	e.fill(entry, 85, .085)
+
+	// Возврат к средней закрывает позицию за вычетом комиссии
+	e.feed("BTCUSDT", 100, true)
+	if exit := e.request(); !almostEqual(exit.Order.Qty, -1.175) {
+		t.Fatalf("unexpected exit: %+v", exit.Order)
+	}
+}
+
+func TestPairsStrategyUnwind(t *testing.T) {
+	closesB := []float64{100, 101, 100, 102, 101, 100, 102, 101, 100, 101, 102, 101}
+	closesA := make([]float64, len(closesB))
+	for i, c := range closesB {
+		closesA[i] = c * (1 + .001*float64(i%2*2-1))
+	}
+	e := newStrategyEnv(t, "linear", map[string][]float64{"AUSDT": closesA, "BUSDT": closesB})
+	entryZ, closeOnStop := 1.5, false
+	s, err := strategies.NewPairsStrategy(&strategies.PairsConfig{
+		SymbolA:     "AUSDT",
+		SymbolB:     "BUSDT",
+		Interval:    "1",
+		Lookback:    10,
+		EntryZ:      &entryZ,
+		LegAmt:      100,
+		CloseOnStop: &closeOnStop,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.launch(s)()
+
+	// Спред выше средней: продажа A и покупка B
+	e.feed("AUSDT", 110, true)
+	e.feed("BUSDT", 101, true)
+	legA, legB := e.request(), e.request()
+	if legA.Order.Symbol != "AUSDT" || !almostEqual(legA.Order.Qty, -.909) || legB.Order.Qty <= 0 {
+		t.Fatalf("unexpected entry legs: %+v %+v", legA.Order, legB.Order)
+	}
+
+	// Нога B не размещена: исполненная нога A закрывается
+	e.fill(legA, 110, 0)
+	e.reply(legB, "order rejected")
+	unwind := e.request()
+	if unwind.Tag != "pairs-unwind" || unwind.Order.Symbol != "AUSDT" || !almostEqual(unwind.Order.Qty, .909) {
+		t.Fatalf("unexpected unwind: %+v %+v", unwind, unwind.Order)
+	}
+
+	// Пока закрытие не завершено, пара занята и новый вход не выставляется
+	e.feed("AUSDT", 110, true)
+	e.feed("BUSDT", 101, true)
+	e.noRequest()
+
+	e.fill(unwind, 110, 0)
+	time.Sleep(50 * time.Millisecond)
+	if p := s.Positions(); p[0].Qty != 0 || p[1].Qty != 0 {
+		t.Fatalf("unexpected positions after unwind: %+v", p)
+	}
+}
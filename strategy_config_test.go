@@ -0,0 +1,90 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/trading"
+	"github.com/nikita55612/goTradingBot/internal/trading/strategies"
+)
+
+func TestStrategyConfigRegistry(t *testing.T) {
+	var cfg trading.TradingBotConfig
+	data := []byte(`{"strategies": [
+		{"type": "trend", "params": {"symbol": "BTCUSDT", "interval": "M15", "availableBalance": 20}},
+		{"symbol": "ETHUSDT", "interval": "M5", "availableBalance": 10, "longRatio": 0.3},
+		{"type": "trend", "params": {"symbol": "BTCUSDT", "interval": "M7", "availableBalance": 20, "longRatio": 2}},
+		{"type": "unknown", "params": {}}
+	]}`)
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, wantErr := range []bool{false, false, true, true} {
+		s, err := trading.NewStrategy(&cfg.Strategies[i])
+		if (err != nil) != wantErr {
+			t.Fatalf("strategy %d: unexpected error state: %v", i, err)
+		}
+		if err == nil {
+			if _, ok := s.(*strategies.TrendStrategy); !ok {
+				t.Fatalf("strategy %d: unexpected type %T", i, s)
+			}
+		}
+	}
+}
+
+func TestTradingBotConfigAccounts(t *testing.T) {
+	load := func(data string) (*trading.TradingBotConfig, error) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return trading.LoadTradingBotConfig(path)
+	}
+
+	cfg, err := load(`{"category": "spot", "tradeWS": true, "strategies": [{"symbol": "BTCUSDT"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Accounts) != 1 || cfg.Accounts[0].Exchange != trading.ExchangeBybit ||
+		cfg.Accounts[0].Category != "spot" || !cfg.Accounts[0].TradeWS || cfg.Strategies[0].Account != trading.DefaultAccountName {
+		t.Fatalf("unexpected legacy config: %+v", cfg)
+	}
+
+	cfg, err = load(`{"accounts": [
+		{"name": "main", "exchange": "bybit", "category": "linear"},
+		{"name": "okx", "exchange": "okx", "network": "demo", "credentials": {"env": "OKX_SUB"}}
+	], "strategies": [
+		{"symbol": "BTCUSDT"},
+		{"type": "trend", "account": "okx", "params": {"symbol": "BTC-USDT-SWAP"}},
+		{"account": "okx", "symbol": "ETH-USDT-SWAP"}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := cfg.StrategiesByAccount()
+	if len(groups["main"]) != 1 || len(groups["okx"]) != 2 {
+		t.Fatalf("unexpected strategy groups: %+v", groups)
+	}
+	var params map[string]any
+	json.Unmarshal(groups["okx"][1].Params, &params)
+	if _, ok := params["account"]; ok || params["symbol"] != "ETH-USDT-SWAP" {
+		t.Fatalf("account leaked into flat params: %s", groups["okx"][1].Params)
+	}
+	if cfg.Accounts[1].Category != "linear" || cfg.Accounts[1].Credentials.EnvPrefix("okx") != "OKX_SUB" ||
+		cfg.Accounts[0].Network != trading.NetworkMainnet || cfg.Accounts[0].Credentials.EnvPrefix("bybit") != "BYBIT" {
+		t.Fatalf("unexpected accounts: %+v", cfg.Accounts)
+	}
+
+	for _, data := range []string{
+		`{"accounts": [{"name": "a", "exchange": "bybit"}], "strategies": [{"account": "b"}]}`,
+		`{"accounts": [{"name": "a", "exchange": "kraken"}], "strategies": []}`,
+		`{"accounts": [{"name": "a", "exchange": "bybit"}, {"name": "a", "exchange": "okx"}], "strategies": []}`,
+	} {
+		if _, err := load(data); err == nil {
+			t.Fatalf("expected error for config: %s", data)
+		}
+	}
+}
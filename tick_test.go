@@ -0,0 +1,54 @@
+package main_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+	"github.com/nikita55612/goTradingBot/internal/pkg/tick"
+)
+
+func TestTickAggregators(t *testing.T) {
+	ticks := []*tick.Tick{
+		{Time: mtfBase, Side: tick.Buy, Price: 100, Size: 1},
+		{Time: mtfBase + 500, Side: tick.Sell, Price: 99, Size: 3},
+		{Time: mtfBase + 2_000, Side: tick.Buy, Price: 101, Size: 1},
+		{Time: mtfBase + 60_000, Side: tick.Buy, Price: 102, Size: 50},
+	}
+
+	delta := tick.NewVolumeDelta(cdl.M1)
+	builder := tick.NewCandleBuilder(time.Second)
+	detector := tick.NewLargeTradeDetector(1000, 3, 10)
+
+	var bars []tick.DeltaBar
+	var candles []cdl.Candle
+	var large []*tick.Tick
+	for _, tk := range ticks {
+		if bar, ok := delta.Add(tk); ok {
+			bars = append(bars, bar)
+		}
+		if c, ok := builder.Add(tk); ok {
+			candles = append(candles, c)
+		}
+		if detector.Check(tk) {
+			large = append(large, tk)
+		}
+	}
+
+	if len(bars) != 1 || bars[0].Delta() != -1 || bars[0].Trades != 3 {
+		t.Fatalf("unexpected delta bars: %+v", bars)
+	}
+	if len(candles) != 2 || candles[0].H != 100 || candles[0].L != 99 || candles[0].Volume != 4 {
+		t.Fatalf("unexpected tick candles: %+v", candles)
+	}
+	if len(large) != 1 || large[0].Size != 50 {
+		t.Fatalf("unexpected large trades: %+v", large)
+	}
+
+	if _, ok := builder.Flush(mtfBase + 60_500); ok {
+		t.Fatal("candle flushed before its period ended")
+	}
+	if c, ok := builder.Flush(mtfBase + 62_000); !ok || c.Volume != 50 {
+		t.Fatalf("unexpected flushed candle: %+v", c)
+	}
+}
@@ -0,0 +1,69 @@
+package main_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit"
+)
+
+func TestBybitTickerDeltaMerge(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if !strings.Contains(string(msg), `"subscribe"`) || !strings.Contains(string(msg), "tickers.BTCUSDT") {
+				continue
+			}
+			for _, m := range []string{
+				// Изменение до снимка пропускается
+				`{"topic":"tickers.BTCUSDT","type":"delta","ts":1,"data":{"symbol":"BTCUSDT","lastPrice":"1"}}`,
+				`{"topic":"tickers.BTCUSDT","type":"snapshot","ts":2,"data":{"symbol":"BTCUSDT","lastPrice":"100",
+					"markPrice":"101","bid1Price":"99","ask1Price":"102","fundingRate":"0.0001","nextFundingTime":"3600000"}}`,
+				`{"topic":"tickers.BTCUSDT","type":"delta","ts":3,"data":{"symbol":"BTCUSDT","lastPrice":"105","bid1Price":"104"}}`,
+			} {
+				conn.WriteMessage(websocket.TextMessage, []byte(m))
+			}
+		}
+	}))
+	defer server.Close()
+
+	cli := bybit.NewClient("key", "secret", bybit.WithPublicWS("ws"+strings.TrimPrefix(server.URL, "http")))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := cli.TickerStream(ctx, "BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receive := func() (ts int64, last, mark, bid, ask float64) {
+		select {
+		case tk := <-stream:
+			return tk.Time, tk.LastPrice, tk.MarkPrice, tk.BidPrice, tk.AskPrice
+		case <-time.After(2 * time.Second):
+			t.Fatal("ticker not received")
+		}
+		return
+	}
+
+	if ts, last, mark, bid, ask := receive(); ts != 2 || last != 100 || mark != 101 || bid != 99 || ask != 102 {
+		t.Fatalf("unexpected snapshot: %d %f %f %f %f", ts, last, mark, bid, ask)
+	}
+	// Изменение обновляет только переданные поля
+	if ts, last, mark, bid, ask := receive(); ts != 3 || last != 105 || mark != 101 || bid != 104 || ask != 102 {
+		t.Fatalf("unexpected merged ticker: %d %f %f %f %f", ts, last, mark, bid, ask)
+	}
+}
@@ -0,0 +1,93 @@
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nikita55612/goTradingBot/internal/broker/bybit"
+)
+
+// newTradeServer имитирует WebSocket API ордеров (/v5/trade) и REST API биржи
+func newTradeServer(restCalls *atomic.Int32) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v5/trade", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req struct {
+				ReqId string           `json:"reqId"`
+				Op    string           `json:"op"`
+				Args  []map[string]any `json:"args"`
+			}
+			json.Unmarshal(msg, &req)
+			resp := map[string]any{"reqId": req.ReqId, "retCode": 0, "retMsg": "OK", "op": req.Op}
+			switch req.Op {
+			case "auth":
+			case "order.create":
+				resp["data"] = map[string]any{"orderId": "ws-order"}
+			case "order.cancel":
+				if req.Args[0]["symbol"] != "BTCUSDT" {
+					resp["retCode"], resp["retMsg"] = 10001, "symbol is required"
+				}
+				resp["data"] = map[string]any{"orderId": req.Args[0]["orderId"]}
+			default:
+				continue
+			}
+			conn.WriteJSON(resp)
+		}
+	})
+	mux.HandleFunc("/v5/order/create", func(w http.ResponseWriter, r *http.Request) {
+		restCalls.Add(1)
+		w.Write([]byte(`{"retCode":0,"retMsg":"OK","result":{"orderId":"rest-order"}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestTradeWSFallback(t *testing.T) {
+	var restCalls atomic.Int32
+	server := newTradeServer(&restCalls)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v5/trade"
+
+	// Соединение открывается в фоне: первый ордер отправляется через REST
+	cli := bybit.NewClient("key", "secret", bybit.WithBaseURL(server.URL), bybit.WithTradeWS(wsURL))
+	orderId, err := cli.PlaceOrder("BTCUSDT", 0.01, nil)
+	if err != nil || orderId != "rest-order" || restCalls.Load() != 1 {
+		t.Fatalf("unexpected rest order: %s %v (rest calls %d)", orderId, err, restCalls.Load())
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for orderId != "ws-order" && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		if orderId, err = cli.PlaceOrder("BTCUSDT", 0.01, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if orderId != "ws-order" {
+		t.Fatalf("trade websocket is not ready (rest calls %d)", restCalls.Load())
+	}
+	if _, err := cli.CancelOrder(orderId); err != nil {
+		t.Fatal(err)
+	}
+
+	// Соединение недоступно: ордер отправляется через REST
+	restCalls.Store(0)
+	cli = bybit.NewClient("key", "secret", bybit.WithBaseURL(server.URL), bybit.WithTradeWS(wsURL+"-missing"))
+	orderId, err = cli.PlaceOrder("BTCUSDT", 0.01, nil)
+	if err != nil || orderId != "rest-order" || restCalls.Load() != 1 {
+		t.Fatalf("unexpected rest order: %s %v (rest calls %d)", orderId, err, restCalls.Load())
+	}
+}
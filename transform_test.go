@@ -0,0 +1,68 @@
+package main_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nikita55612/goTradingBot/internal/pkg/cdl"
+)
+
+func TestBarTransforms(t *testing.T) {
+	closes := []float64{100, 101, 103, 104, 101, 98, 99}
+	candles := make([]cdl.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = cdl.Candle{Time: int64(i), O: c, H: c + 1, L: c - 1, C: c, Volume: 10}
+	}
+
+	ha := cdl.Transform(candles, cdl.NewHeikinAshi())
+	if len(ha) != len(candles) || !almostEqual(ha[1].O, 100) || !almostEqual(ha[1].C, 101) {
+		t.Fatalf("unexpected heikin-ashi bars: %+v", ha[:2])
+	}
+
+	renko := cdl.Transform(candles, cdl.NewRenko(2))
+	if len(renko) != 4 || renko[1].C != 104 || renko[2].O != 102 || renko[3].C != 98 || renko[3].Volume != 0 {
+		t.Fatalf("unexpected renko bricks: %+v", renko)
+	}
+	atrRenko := cdl.NewATRRenko(3)
+	cdl.Transform(candles, atrRenko)
+	if atrRenko.BoxSize() <= 0 {
+		t.Fatal("atr box size not computed")
+	}
+
+	for _, bar := range cdl.Transform(candles, cdl.NewRangeBars(3)) {
+		if !almostEqual(bar.H-bar.L, 3) {
+			t.Fatalf("unexpected range bar: %+v", bar)
+		}
+	}
+
+	volume := cdl.Transform(candles, cdl.NewVolumeBars(25))
+	if len(volume) != 2 || volume[0].Volume != 30 || volume[0].O != 100 || volume[0].C != 103 {
+		t.Fatalf("unexpected volume bars: %+v", volume)
+	}
+}
+
+func TestTransformStream(t *testing.T) {
+	in := make(chan *cdl.CandleStreamData)
+	out := cdl.TransformStream(context.Background(), in, cdl.NewVolumeBars(20))
+
+	send := func(volume float64, confirm bool) *cdl.CandleStreamData {
+		in <- &cdl.CandleStreamData{
+			Candle:   cdl.Candle{O: 1, H: 1, L: 1, C: 1, Volume: volume},
+			Interval: cdl.M1,
+			Confirm:  confirm,
+		}
+		return <-out
+	}
+
+	if data := send(5, false); data.Confirm || data.Candle.Volume != 5 {
+		t.Fatalf("unexpected preview bar: %+v", data)
+	}
+	if data := send(15, false); data.Confirm || data.Candle.Volume != 15 {
+		t.Fatalf("preview changed builder state: %+v", data)
+	}
+	in <- &cdl.CandleStreamData{Candle: cdl.Candle{Volume: 10}, Interval: cdl.M1, Confirm: true}
+	if data := send(10, true); !data.Confirm || data.Candle.Volume != 20 {
+		t.Fatalf("unexpected confirmed bar: %+v", data)
+	}
+	close(in)
+}
@@ -0,0 +1,204 @@
+package main_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nikita55612/goTradingBot/internal/pkg/ws"
+)
+
+func TestWSConnResubscribe(t *testing.T) {
+	received := make(chan string, 16)
+	connections := 0
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connections++
+		first := connections == 1
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(msg)
+			if string(msg) == "send" {
+				conn.WriteMessage(websocket.TextMessage, []byte("reply"))
+				if first {
+					// Разрыв соединения сервером после первого ответа
+					return
+				}
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := ws.Dial(
+		"ws"+strings.TrimPrefix(server.URL, "http"),
+		ctx,
+		ws.WithHandshakeFunc(func() [][]byte { return [][]byte{[]byte("subscribe")} }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(want string) {
+		select {
+		case msg := <-received:
+			if msg != want {
+				t.Fatalf("unexpected message: %q, want %q", msg, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %q not received", want)
+		}
+	}
+
+	expect("subscribe")
+	if err := conn.Send([]byte("send")); err != nil {
+		t.Fatal(err)
+	}
+	expect("send")
+	if msg := <-conn.Messages(); string(msg) != "reply" {
+		t.Fatalf("unexpected reply: %q", msg)
+	}
+	// После переподключения рукопожатие отправляется повторно
+	expect("subscribe")
+}
+
+func TestWSConnReconnectPolicy(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	drop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		select {
+		case <-drop:
+		case <-r.Context().Done():
+		}
+	}))
+
+	policy := ws.ReconnectPolicy{MinDelay: 20 * time.Millisecond, Multiplier: 2, MaxAttempts: 2}
+	conn, err := ws.Dial(
+		"ws"+strings.TrimPrefix(server.URL, "http"),
+		context.Background(),
+		ws.WithReconnectPolicy(policy),
+		ws.WithHandshakeFunc(func() [][]byte { return [][]byte{[]byte("subscribe")} }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := make(chan ws.StateEvent, 16)
+	conn.SubscribeState(states)
+
+	expect := func(want ws.State) ws.StateEvent {
+		t.Helper()
+		select {
+		case event := <-states:
+			if event.State != want {
+				t.Fatalf("unexpected state: %+v, want %s", event, want)
+			}
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("state %s not received", want)
+		}
+		return ws.StateEvent{}
+	}
+
+	// Разрыв соединения сервером: переподключение и повторная подписка
+	drop <- struct{}{}
+	expect(ws.Disconnected)
+	if event := expect(ws.Connecting); event.Attempt != 1 {
+		t.Fatalf("unexpected attempt: %d", event.Attempt)
+	}
+	expect(ws.Connected)
+	expect(ws.Resubscribed)
+	if stats := conn.Stats(); stats.Reconnects != 1 || stats.State != ws.Resubscribed {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// Сервер недоступен: после исчерпания попыток соединение завершается
+	server.Close()
+	close(drop)
+	expect(ws.Disconnected)
+	for range policy.MaxAttempts {
+		expect(ws.Connecting)
+		expect(ws.Disconnected)
+	}
+	if event := expect(ws.Disconnected); !errors.Is(event.Err, ws.ErrReconnectFailed) {
+		t.Fatalf("unexpected terminal error: %v", event.Err)
+	}
+	if _, ok := <-states; ok {
+		t.Fatal("state channel is not closed")
+	}
+	if _, ok := <-conn.Messages(); ok {
+		t.Fatal("messages channel is not closed")
+	}
+}
+
+func TestWSConnRequest(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req struct {
+				ReqID string `json:"reqId"`
+			}
+			json.Unmarshal(msg, &req)
+			if req.ReqID == "silent" {
+				continue
+			}
+			// Сообщение потока перед ответом на запрос
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"topic":"order"}`))
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"reqId":"`+req.ReqID+`","retCode":0}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := ws.Dial(
+		"ws"+strings.TrimPrefix(server.URL, "http"),
+		ctx,
+		ws.WithRequestTimeout(200*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for range conn.Messages() {
+		}
+	}()
+
+	resp, err := conn.Request(ctx, "1", []byte(`{"reqId":"1","op":"order.create"}`))
+	if err != nil || string(resp) != `{"reqId":"1","retCode":0}` {
+		t.Fatalf("unexpected response: %s %v", resp, err)
+	}
+	if _, err := conn.Request(ctx, "silent", []byte(`{"reqId":"silent"}`)); !errors.Is(err, ws.ErrRequestTimeout) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}